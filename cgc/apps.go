@@ -0,0 +1,201 @@
+package cgc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// LatestRevision is used in place of a revision number to refer to the latest revision of an app.
+const LatestRevision = -1
+
+// App struct represents the app information returned from CGC API. Raw holds the CWL description of the app.
+type App struct {
+	Href     string                 `json:"href"`
+	ID       string                 `json:"id"`
+	Project  string                 `json:"project"`
+	Name     string                 `json:"name"`
+	Revision int                    `json:"revision"`
+	Raw      map[string]interface{} `json:"raw,omitempty"`
+}
+
+// AppRevision describes a single revision of an app.
+type AppRevision struct {
+	Revision   int
+	ModifiedBy string
+	ModifiedOn time.Time
+	Notes      string
+}
+
+// AppFilter narrows down the listed apps. Apps from the Project are listed if it's set, else the apps available
+// to the token holder are listed. If Public is true, the publicly available apps are listed instead.
+type AppFilter struct {
+	Project string
+	Public  bool
+}
+
+// Apps lists all the apps that match the filter.
+func (c Client) Apps(filter AppFilter) ([]App, error) {
+	apps, err := collect(c.IterateApps(filter, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching apps failed: %s", err.Error())
+	}
+	return apps, nil
+}
+
+// IterateApps returns an iterator over the apps that match the filter.
+func (c Client) IterateApps(filter AppFilter, opts ListOptions) *Iterator[App] {
+	u := mustParseURL(c.baseURL)
+	u.Path += "apps"
+	params := url.Values{}
+	if filter.Project != "" {
+		params.Add("project", filter.Project)
+	}
+	if filter.Public {
+		params.Add("visibility", "public")
+	}
+	u.RawQuery = params.Encode()
+	return newIterator[App](c, u, opts)
+}
+
+// StatApp gets the details of the given revision of the app that has the ID of appID, including its CWL description.
+func (c Client) StatApp(appID string, revision int) (App, error) {
+	resp, err := c.request(http.MethodGet, c.appURL(appID, revision, ""), nil)
+	if err != nil {
+		return App{}, fmt.Errorf("fetching app details failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var app App
+	if err := json.NewDecoder(resp).Decode(&app); err != nil {
+		return App{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return app, nil
+}
+
+// RawApp gets the CWL description of the given revision of the app that has the ID of appID.
+func (c Client) RawApp(appID string, revision int) (map[string]interface{}, error) {
+	resp, err := c.request(http.MethodGet, c.appURL(appID, revision, "raw"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching raw app failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var raw map[string]interface{}
+	if err := json.NewDecoder(resp).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return raw, nil
+}
+
+// AppRevisions lists the revisions of the app that has the ID of appID, as recorded in its CWL description.
+// Revisions are sorted from the oldest to the latest.
+func (c Client) AppRevisions(appID string) ([]AppRevision, error) {
+	raw, err := c.RawApp(appID, LatestRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, _ := raw["sbg:revisionsInfo"].([]interface{})
+	revisions := make([]AppRevision, 0, len(infos))
+	for _, i := range infos {
+		info, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var revision AppRevision
+		if n, ok := info["sbg:revision"].(float64); ok {
+			revision.Revision = int(n)
+		}
+		if n, ok := info["sbg:modifiedOn"].(float64); ok {
+			revision.ModifiedOn = time.Unix(int64(n), 0)
+		}
+		revision.ModifiedBy, _ = info["sbg:modifiedBy"].(string)
+		revision.Notes, _ = info["sbg:revisionNotes"].(string)
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+
+	return revisions, nil
+}
+
+// CopyApp copies the app that has the ID of appID into the project with projectID. The copy keeps the name of
+// the original app unless a new name is given.
+func (c Client) CopyApp(appID, projectID, name string) (App, error) {
+	body := struct {
+		Project string `json:"project"`
+		Name    string `json:"name,omitempty"`
+	}{projectID, name}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return App{}, fmt.Errorf("encoding copy request failed: %s", err.Error())
+	}
+
+	u := c.appURL(appID, LatestRevision, "actions/copy")
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return App{}, fmt.Errorf("copying app failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var app App
+	if err := json.NewDecoder(resp).Decode(&app); err != nil {
+		return App{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return app, nil
+}
+
+// CreateApp creates a new app with the ID of appID (in format 'owner/project/name') out of the CWL description.
+func (c Client) CreateApp(appID string, raw map[string]interface{}) (App, error) {
+	return c.pushRaw(c.appURL(appID, LatestRevision, "raw"), raw)
+}
+
+// PushApp creates a new revision of the app that has the ID of appID out of the CWL description. Returns the app as
+// it is at the new revision.
+func (c Client) PushApp(appID string, raw map[string]interface{}) (App, error) {
+	latest, err := c.StatApp(appID, LatestRevision)
+	if err != nil {
+		return App{}, err
+	}
+	return c.pushRaw(c.appURL(appID, latest.Revision+1, "raw"), raw)
+}
+
+// pushRaw sends the CWL description of an app to u.
+func (c Client) pushRaw(u *url.URL, raw map[string]interface{}) (App, error) {
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return App{}, fmt.Errorf("encoding raw app failed: %s", err.Error())
+	}
+
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return App{}, fmt.Errorf("pushing raw app failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var app App
+	if err := json.NewDecoder(resp).Decode(&app); err != nil {
+		return App{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return app, nil
+}
+
+// appURL returns the URL of the app that has the ID of appID at the given revision, with the suffix appended to
+// the path.
+func (c Client) appURL(appID string, revision int, suffix string) *url.URL {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("apps/%s/", appID)
+	if revision != LatestRevision {
+		u.Path += fmt.Sprintf("%d/", revision)
+	}
+	u.Path += suffix
+	return u
+}
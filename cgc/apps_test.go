@@ -0,0 +1,80 @@
+package cgc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testAppID = "user/project/app"
+
+func handleApps(w http.ResponseWriter, r *http.Request) {
+	raw := map[string]interface{}{
+		"class": "CommandLineTool",
+		"sbg:revisionsInfo": []map[string]interface{}{
+			{"sbg:revision": 1, "sbg:modifiedBy": "user", "sbg:modifiedOn": 1500000000, "sbg:revisionNotes": "fix"},
+			{"sbg:revision": 0, "sbg:modifiedBy": "user", "sbg:modifiedOn": 1400000000},
+		},
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/apps/")
+
+	var resp interface{}
+	switch {
+	case r.Method == http.MethodGet && path == testAppID+"/":
+		resp = App{ID: testAppID, Revision: 1, Raw: raw}
+	case r.Method == http.MethodGet && path == testAppID+"/raw":
+		resp = raw
+	case r.Method == http.MethodPost && path == testAppID+"/2/raw":
+		resp = App{ID: testAppID, Revision: 2}
+	case r.Method == http.MethodPost && path == testAppID+"/actions/copy":
+		var body struct {
+			Project string `json:"project"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp = App{ID: body.Project + "/app", Project: body.Project}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestApps(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleApps)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	revisions, err := client.AppRevisions(testAppID)
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 0 || revisions[1].Notes != "fix" {
+		t.Fatalf("unexpected revisions: %+v", revisions)
+	}
+
+	app, err := client.PushApp(testAppID, map[string]interface{}{"class": "CommandLineTool"})
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if app.Revision != 2 {
+		t.Fatalf("expected revision 2, got %d", app.Revision)
+	}
+
+	app, err = client.CopyApp(testAppID, "user/other", "")
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if app.Project != "user/other" {
+		t.Fatalf("expected app copied to 'user/other', got '%s'", app.Project)
+	}
+}
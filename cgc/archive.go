@@ -0,0 +1,71 @@
+package cgc
+
+import (
+	"context"
+	"fmt"
+)
+
+// Storage classes a file can be stored in.
+const (
+	StorageStandard = "STANDARD"
+	StorageArchived = "ARCHIVED"
+)
+
+// Statuses of restoring an archived file.
+const (
+	RestoreInProgress = "IN_PROGRESS"
+	RestoreCompleted  = "COMPLETED"
+	RestoreFailed     = "FAILED"
+)
+
+// FileActionResult is the outcome of a bulk request for a single file, e.g. archiving, restoring or fetching it.
+type FileActionResult struct {
+	FileID string
+	File   File
+	Err    error
+}
+
+// Archived reports whether the file is in the archive storage class and has to be restored before it can be used.
+func (f File) Archived() bool {
+	return f.Storage.Class == StorageArchived && f.Storage.RestoreStatus != RestoreCompleted
+}
+
+// Restoring reports whether the file is being restored from the archive.
+func (f File) Restoring() bool {
+	return f.Storage.RestoreStatus == RestoreInProgress
+}
+
+// ArchiveFiles moves the files with the given IDs to the archive storage class, splitting them into as many bulk
+// requests as needed. A file that couldn't be archived doesn't stop the others, the outcome for each one is returned
+// in the same order the IDs were given in.
+func (c Client) ArchiveFiles(fileIDs []string) []FileActionResult {
+	return c.fileAction("archive", fileIDs)
+}
+
+// RestoreFiles starts restoring the archived files with the given IDs, the same way ArchiveFiles archives them.
+// Restoring takes hours, WaitRestore can be used to wait for it to finish.
+func (c Client) RestoreFiles(fileIDs []string) []FileActionResult {
+	return c.fileAction("restore", fileIDs)
+}
+
+// WaitRestore blocks until the file that has the ID of fileID is no longer being restored or the context is done,
+// polling the file the same way WaitTask polls tasks. Every time the restore status of the file changes, onChange is
+// called with the file. The returned file tells whether the restore completed or failed.
+func (c Client) WaitRestore(ctx context.Context, fileID string, onChange func(File)) (File, error) {
+	changed := func(before, after File) bool {
+		return before.Storage.Class != after.Storage.Class || before.Storage.RestoreStatus != after.Storage.RestoreStatus
+	}
+	done := func(f File) bool { return !f.Restoring() }
+	return waitUntilDone(ctx, fileID, c.WithCache(nil).StatFile, changed, done, onChange)
+}
+
+// fileAction performs the bulk action on the files with the given IDs. The files of a bulk request that failed as a
+// whole are returned with the error of that request.
+func (c Client) fileAction(action string, fileIDs []string) []FileActionResult {
+	items := bulkRequest[string, File](c, fmt.Sprintf("bulk/files/actions/%s", action), "file_ids", fileIDs)
+	results := make([]FileActionResult, 0, len(fileIDs))
+	for i, item := range items {
+		results = append(results, FileActionResult{fileIDs[i], item.Resource, item.err()})
+	}
+	return results
+}
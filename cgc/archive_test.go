@@ -0,0 +1,114 @@
+package cgc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func handleArchive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/bulk/files/actions/archive" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var req struct {
+		FileIDs []string `json:"file_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if contains(req.FileIDs, "unavailable") {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var resp struct {
+		Items []bulkItem[File] `json:"items"`
+	}
+	for _, id := range req.FileIDs {
+		if id == "missing" {
+			msg := apiErrorResponseTemplate{Message: "file not found"}
+			resp.Items = append(resp.Items, bulkItem[File]{Error: &msg})
+			continue
+		}
+		file := File{ID: id, Storage: fileStorage{Class: StorageArchived}}
+		resp.Items = append(resp.Items, bulkItem[File]{Resource: file})
+	}
+	if err := json.NewEncoder(w).Encode(&resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestArchiveFiles(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleArchive)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	results := client.ArchiveFiles([]string{"a", "missing", "b"})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[1].FileID != "missing" || results[1].Err == nil {
+		t.Fatalf("expected archiving 'missing' to fail, got %+v", results[1])
+	}
+	if results[2].FileID != "b" || results[2].Err != nil || !results[2].File.Archived() {
+		t.Fatalf("expected 'b' to be archived, got %+v", results[2])
+	}
+
+	ids := make([]string, 2*bulkMaxItems)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("file-%d", i)
+	}
+	ids[len(ids)-1] = "unavailable"
+	results = client.ArchiveFiles(ids)
+	if len(results) != len(ids) {
+		t.Fatalf("expected %d results, got %d", len(ids), len(results))
+	}
+	for i, r := range results {
+		if failed := i >= bulkMaxItems; failed != (r.Err != nil) {
+			t.Fatalf("expected only the files of the failed request to have an error, got %+v for file %d", r, i)
+		}
+	}
+}
+
+func TestWaitRestore(t *testing.T) {
+	setWaitIntervals(t)
+
+	statuses := []string{RestoreInProgress, RestoreInProgress, RestoreCompleted}
+	polls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file := File{ID: "a", Storage: fileStorage{Class: StorageArchived, RestoreStatus: statuses[polls]}}
+		if polls < len(statuses)-1 {
+			polls++
+		}
+		if err := json.NewEncoder(w).Encode(&file); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+	client := New("")
+	client.baseURL = ts.URL + "/"
+
+	changes := make([]string, 0)
+	file, err := client.WaitRestore(context.Background(), "a", func(f File) {
+		changes = append(changes, f.Storage.RestoreStatus)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if file.Archived() {
+		t.Fatalf("expected file to be restored, got %+v", file.Storage)
+	}
+	if strings.Join(changes, ",") != "IN_PROGRESS,COMPLETED" {
+		t.Fatalf("expected 'IN_PROGRESS,COMPLETED' changes, got '%s'", strings.Join(changes, ","))
+	}
+}
@@ -0,0 +1,84 @@
+package cgc
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Types of criteria a batch task can split its batch input by.
+const (
+	BatchItem     = "ITEM"
+	BatchCriteria = "CRITERIA"
+)
+
+// BatchByCriteria returns batch criteria that split the batch input into groups of files sharing the values of the
+// given fields (e.g. 'metadata.sample_id'). Without any fields, every file gets its own child task.
+func BatchByCriteria(fields ...string) *BatchBy {
+	if len(fields) == 0 {
+		return &BatchBy{Type: BatchItem}
+	}
+	return &BatchBy{Type: BatchCriteria, Criteria: fields}
+}
+
+// FileGroup is a group of files sharing the same value of a metadata field.
+type FileGroup struct {
+	Value string
+	Files []File
+}
+
+// GroupFiles groups the files by the value of the metadata field key. Files that don't have the field set are
+// returned separately. Groups are sorted by value and files keep their order within a group.
+func GroupFiles(files []File, key string) (groups []FileGroup, ungrouped []File) {
+	byValue := make(map[string][]File)
+	for _, file := range files {
+		value, ok := file.Metadata[key]
+		if !ok || value == nil || fmt.Sprint(value) == "" {
+			ungrouped = append(ungrouped, file)
+			continue
+		}
+		byValue[fmt.Sprint(value)] = append(byValue[fmt.Sprint(value)], file)
+	}
+
+	for value, files := range byValue {
+		groups = append(groups, FileGroup{Value: value, Files: files})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Value < groups[j].Value })
+
+	return groups, ungrouped
+}
+
+// TaskSubmission is the outcome of submitting a single task with SubmitTasks. Task holds the task as it was
+// created if the submission succeeded, else Err describes why it failed.
+type TaskSubmission struct {
+	Request Task
+	Task    Task
+	Err     error
+}
+
+// SubmitTasks creates all the tasks, running at most concurrency requests at a time. If run is true, every task
+// is run right after it's created. Submissions are returned in the same order as the tasks, and a failed
+// submission doesn't stop the other ones.
+func (c Client) SubmitTasks(tasks []Task, run bool, concurrency int) []TaskSubmission {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	submissions := make([]TaskSubmission, len(tasks))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, task Task) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			created, err := c.CreateTask(task, run)
+			submissions[i] = TaskSubmission{Request: task, Task: created, Err: err}
+		}(i, task)
+	}
+	wg.Wait()
+
+	return submissions
+}
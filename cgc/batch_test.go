@@ -0,0 +1,57 @@
+package cgc
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGroupFiles(t *testing.T) {
+	files := []File{
+		{ID: "1", Metadata: map[string]interface{}{"sample_id": "b"}},
+		{ID: "2", Metadata: map[string]interface{}{"sample_id": "a"}},
+		{ID: "3", Metadata: map[string]interface{}{"sample_id": "b"}},
+		{ID: "4", Metadata: map[string]interface{}{"case_id": "a"}},
+		{ID: "5", Metadata: map[string]interface{}{"sample_id": nil}},
+	}
+
+	groups, ungrouped := GroupFiles(files, "sample_id")
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].Value != "a" || len(groups[0].Files) != 1 {
+		t.Fatalf("expected group 'a' with 1 file, got '%s' with %d", groups[0].Value, len(groups[0].Files))
+	}
+	if groups[1].Value != "b" || len(groups[1].Files) != 2 || groups[1].Files[0].ID != "1" {
+		t.Fatalf("expected group 'b' with files 1 and 3, got '%s' with %+v", groups[1].Value, groups[1].Files)
+	}
+	if len(ungrouped) != 2 {
+		t.Fatalf("expected 2 ungrouped files, got %d", len(ungrouped))
+	}
+}
+
+func TestSubmitTasks(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleTasks)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	tasks := make([]Task, 10)
+	for i := range tasks {
+		tasks[i] = Task{Project: testProjectID, App: "user/project/app", Name: string(rune('a' + i))}
+	}
+
+	submissions := client.SubmitTasks(tasks, true, 3)
+	if len(submissions) != len(tasks) {
+		t.Fatalf("expected %d submissions, got %d", len(tasks), len(submissions))
+	}
+	for i, s := range submissions {
+		if s.Err != nil {
+			t.Fatalf("expected no error, got '%v'", s.Err)
+		}
+		if s.Task.Name != tasks[i].Name || s.Task.Status != TaskQueued {
+			t.Fatalf("expected queued task '%s', got '%s' in '%s'", tasks[i].Name, s.Task.Name, s.Task.Status)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package cgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Ways the costs of a billing group can be aggregated.
+const (
+	CostByProject = "project"
+	CostByTask    = "task"
+	CostByUser    = "user"
+)
+
+// breakdownDateFormat is the format of the dates the breakdown endpoints are filtered by.
+const breakdownDateFormat = "2006-01-02"
+
+// Money struct represents an amount of money returned from CGC API. The API returns amounts both as numbers and as
+// strings holding numbers, both are accepted.
+type Money struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+// UnmarshalJSON decodes the amount whether it's encoded as a number or as a string.
+func (m *Money) UnmarshalJSON(bs []byte) error {
+	var raw struct {
+		Currency string      `json:"currency"`
+		Amount   json.Number `json:"amount"`
+	}
+	if err := json.Unmarshal(bs, &raw); err != nil {
+		return err
+	}
+	m.Currency = raw.Currency
+	m.Amount = 0
+	if raw.Amount != "" {
+		amount, err := strconv.ParseFloat(string(raw.Amount), 64)
+		if err != nil {
+			return fmt.Errorf("malformed amount '%s'", raw.Amount)
+		}
+		m.Amount = amount
+	}
+	return nil
+}
+
+// BillingGroup struct represents the billing group information returned from CGC API.
+type BillingGroup struct {
+	Href     string `json:"href"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Owner    string `json:"owner"`
+	Pending  bool   `json:"pending"`
+	Disabled bool   `json:"disabled"`
+	Balance  Money  `json:"balance"`
+}
+
+// AnalysisCost struct represents the cost of a single analysis (a task) in the analysis breakdown of a billing group.
+type AnalysisCost struct {
+	ProjectName  string    `json:"project_name"`
+	AppName      string    `json:"analysis_app_name"`
+	AnalysisName string    `json:"analysis_name"`
+	AnalysisType string    `json:"analysis_type"`
+	AnalysisID   string    `json:"analysis_id"`
+	RanBy        string    `json:"ran_by"`
+	Status       string    `json:"analysis_status"`
+	Cost         Money     `json:"analysis_cost"`
+	TimeStarted  time.Time `json:"time_started"`
+	TimeFinished time.Time `json:"time_finished"`
+}
+
+// StorageUsage struct represents the amount of data stored and what it costs.
+type StorageUsage struct {
+	Size int64 `json:"size"`
+	Cost Money `json:"cost"`
+}
+
+// StorageCost struct represents the cost of storing the files of a single project in the storage breakdown of a
+// billing group.
+type StorageCost struct {
+	ProjectName string       `json:"project_name"`
+	CreatedBy   string       `json:"project_created_by"`
+	Location    string       `json:"location"`
+	Active      StorageUsage `json:"active"`
+	Archived    StorageUsage `json:"archived"`
+}
+
+// CostSummary is the aggregated cost of a project, a task or a user. Name is only set for tasks, whose Key is the
+// task ID. Storage costs are only attributed to projects.
+type CostSummary struct {
+	Key      string  `json:"key"`
+	Name     string  `json:"name,omitempty"`
+	Analysis float64 `json:"analysis_cost"`
+	Storage  float64 `json:"storage_cost"`
+	Total    float64 `json:"total_cost"`
+	Currency string  `json:"currency"`
+	Tasks    int     `json:"tasks"`
+}
+
+// BillingGroups lists all the billing groups the token holder is a member of.
+func (c Client) BillingGroups() ([]BillingGroup, error) {
+	groups, err := collect(c.IterateBillingGroups(ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching billing groups failed: %s", err.Error())
+	}
+	return groups, nil
+}
+
+// IterateBillingGroups returns an iterator over the billing groups the token holder is a member of.
+func (c Client) IterateBillingGroups(opts ListOptions) *Iterator[BillingGroup] {
+	u := mustParseURL(c.baseURL)
+	u.Path += "billing/groups"
+	return newIterator[BillingGroup](c, u, opts)
+}
+
+// StatBillingGroup gets the details of the billing group that has the ID of groupID, including its balance.
+func (c Client) StatBillingGroup(groupID string) (BillingGroup, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("billing/groups/%s", groupID)
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return BillingGroup{}, fmt.Errorf("fetching billing group details failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var group BillingGroup
+	if err := json.NewDecoder(resp).Decode(&group); err != nil {
+		return BillingGroup{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return group, nil
+}
+
+// AnalysisBreakdown lists the costs of all the analyses billed to the billing group with groupID between the two
+// dates (both inclusive). Zero dates don't limit the period.
+func (c Client) AnalysisBreakdown(groupID string, from, to time.Time) ([]AnalysisCost, error) {
+	costs, err := collect(c.IterateAnalysisBreakdown(groupID, from, to, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching analysis breakdown failed: %s", err.Error())
+	}
+	return costs, nil
+}
+
+// IterateAnalysisBreakdown returns an iterator over the costs of the analyses billed to the billing group with
+// groupID between the two dates.
+func (c Client) IterateAnalysisBreakdown(groupID string, from, to time.Time, opts ListOptions) *Iterator[AnalysisCost] {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("billing/groups/%s/breakdown/analysis", groupID)
+	u.RawQuery = breakdownValues(from, to).Encode()
+	return newIterator[AnalysisCost](c, u, opts)
+}
+
+// StorageBreakdown lists the storage costs of all the projects billed to the billing group with groupID between the
+// two dates (both inclusive). Zero dates don't limit the period.
+func (c Client) StorageBreakdown(groupID string, from, to time.Time) ([]StorageCost, error) {
+	costs, err := collect(c.IterateStorageBreakdown(groupID, from, to, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching storage breakdown failed: %s", err.Error())
+	}
+	return costs, nil
+}
+
+// IterateStorageBreakdown returns an iterator over the storage costs of the projects billed to the billing group
+// with groupID between the two dates.
+func (c Client) IterateStorageBreakdown(groupID string, from, to time.Time, opts ListOptions) *Iterator[StorageCost] {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("billing/groups/%s/breakdown/storage", groupID)
+	u.RawQuery = breakdownValues(from, to).Encode()
+	return newIterator[StorageCost](c, u, opts)
+}
+
+// AggregateCosts sums up the analysis and the storage costs by project, task or user. Summaries are sorted by the
+// total cost, the most expensive first. Costs in different currencies can't be summed up and result in an error.
+func AggregateCosts(analysis []AnalysisCost, storage []StorageCost, by string) ([]CostSummary, error) {
+	var key func(AnalysisCost) (string, string)
+	switch by {
+	case CostByProject:
+		key = func(a AnalysisCost) (string, string) { return a.ProjectName, "" }
+	case CostByTask:
+		key = func(a AnalysisCost) (string, string) { return a.AnalysisID, a.AnalysisName }
+	case CostByUser:
+		key = func(a AnalysisCost) (string, string) { return a.RanBy, "" }
+	default:
+		return nil, fmt.Errorf("costs can't be aggregated by '%s'", by)
+	}
+
+	summaries := make(map[string]*CostSummary)
+	currency := ""
+	add := func(k, name string, money Money, isStorage bool) error {
+		if money.Currency != "" {
+			if currency != "" && currency != money.Currency {
+				return fmt.Errorf("costs are in different currencies, '%s' and '%s'", currency, money.Currency)
+			}
+			currency = money.Currency
+		}
+		s, ok := summaries[k]
+		if !ok {
+			s = &CostSummary{Key: k, Name: name}
+			summaries[k] = s
+		}
+		if isStorage {
+			s.Storage += money.Amount
+		} else {
+			s.Analysis += money.Amount
+			s.Tasks++
+		}
+		s.Total += money.Amount
+		return nil
+	}
+
+	for _, a := range analysis {
+		k, name := key(a)
+		if err := add(k, name, a.Cost, false); err != nil {
+			return nil, err
+		}
+	}
+	if by == CostByProject {
+		for _, s := range storage {
+			for _, usage := range []StorageUsage{s.Active, s.Archived} {
+				if err := add(s.ProjectName, "", usage.Cost, true); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	result := make([]CostSummary, 0, len(summaries))
+	for _, s := range summaries {
+		s.Currency = currency
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Key < result[j].Key
+	})
+
+	return result, nil
+}
+
+// breakdownValues encodes the period as query parameters understood by the breakdown endpoints.
+func breakdownValues(from, to time.Time) url.Values {
+	params := url.Values{}
+	if !from.IsZero() {
+		params.Add("date_from", from.Format(breakdownDateFormat))
+	}
+	if !to.IsZero() {
+		params.Add("date_to", to.Format(breakdownDateFormat))
+	}
+	return params
+}
@@ -0,0 +1,82 @@
+package cgc
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMoneyUnmarshal(t *testing.T) {
+	var m []Money
+	in := `[{"currency": "USD", "amount": "12.5"}, {"currency": "USD", "amount": 3}, {"currency": "USD"}]`
+	if err := json.Unmarshal([]byte(in), &m); err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if m[0].Amount != 12.5 || m[1].Amount != 3 || m[2].Amount != 0 {
+		t.Fatalf("unexpected amounts: %+v", m)
+	}
+	if err := json.Unmarshal([]byte(`{"amount": "a lot"}`), &Money{}); err == nil {
+		t.Fatalf("expected malformed amount to fail")
+	}
+}
+
+func TestAggregateCosts(t *testing.T) {
+	usd := func(amount float64) Money { return Money{Currency: "USD", Amount: amount} }
+	analysis := []AnalysisCost{
+		{ProjectName: "p1", AnalysisID: "t1", AnalysisName: "first", RanBy: "ana", Cost: usd(1)},
+		{ProjectName: "p1", AnalysisID: "t2", AnalysisName: "second", RanBy: "bob", Cost: usd(2)},
+		{ProjectName: "p2", AnalysisID: "t3", AnalysisName: "third", RanBy: "ana", Cost: usd(4)},
+	}
+	storage := []StorageCost{
+		{ProjectName: "p1", Active: StorageUsage{Cost: usd(1.5)}, Archived: StorageUsage{Cost: usd(0.5)}},
+	}
+
+	type out struct {
+		keys  string
+		total float64
+		err   error
+	}
+	td := []struct {
+		label   string
+		by      string
+		storage []StorageCost
+		out     out
+	}{
+		{"By project", CostByProject, storage, out{"p1,p2", 5, nil}},
+		{"By task", CostByTask, storage, out{"t3,t2,t1", 4, nil}},
+		{"By user", CostByUser, storage, out{"ana,bob", 5, nil}},
+		{"Unknown", "month", storage, out{"", 0, errors.New("can't be aggregated")}},
+		{
+			"Mixed currencies",
+			CostByProject,
+			[]StorageCost{{ProjectName: "p1", Active: StorageUsage{Cost: Money{"EUR", 1}}}},
+			out{"", 0, errors.New("different currencies")},
+		},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			summaries, err := AggregateCosts(analysis, tt.storage, tt.by)
+			if err != nil {
+				if tt.out.err == nil || !strings.Contains(err.Error(), tt.out.err.Error()) {
+					t.Fatalf("expected '%v', got '%v'", tt.out.err, err)
+				}
+				return
+			}
+			if tt.out.err != nil {
+				t.Fatalf("expected '%v', got no error", tt.out.err)
+			}
+			keys := make([]string, 0, len(summaries))
+			for _, s := range summaries {
+				keys = append(keys, s.Key)
+			}
+			if strings.Join(keys, ",") != tt.out.keys {
+				t.Fatalf("expected '%s' summaries, got '%s'", tt.out.keys, strings.Join(keys, ","))
+			}
+			if summaries[0].Total != tt.out.total || summaries[0].Currency != "USD" {
+				t.Fatalf("expected the top total to be %v USD, got %+v", tt.out.total, summaries[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package cgc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cachedHeaders are the response headers kept together with a cached body. The validators are needed for the
+// conditional requests, the rest is read by the callers of do.
+var cachedHeaders = []string{"Content-Type", "ETag", "Last-Modified", totalHeader}
+
+// Cache keeps the responses of the API on the disk, so listing and inspecting the same resources over and over
+// doesn't have to transfer them every time. Responses with an ETag or a Last-Modified header are revalidated with a
+// conditional request every time they are used, the rest are used as they are until they are older than the TTL.
+// Entries are kept separately for every token, under a hash of it, so the token itself never ends up on the disk.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// CacheStats describes the contents of a cache. Validated entries are the ones revalidated with conditional requests,
+// and expired entries are the ones without validators that are older than the TTL.
+type CacheStats struct {
+	Entries   int
+	Size      int64
+	Validated int
+	Expired   int
+}
+
+type cacheEntry struct {
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredOn time.Time   `json:"stored_on"`
+}
+
+// NewCache returns a cache that keeps the responses in dir. Responses without validators are kept for ttl, or not at
+// all if ttl is zero.
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// WithCache returns a copy of the client that caches the responses of the API in the cache. Only GET requests to the
+// API are cached, except for the ones about tasks and transfers, whose state changes by itself. Any request that
+// isn't read only drops everything cached for the token, since it could have changed any of it. A nil cache turns
+// the caching off.
+func (c Client) WithCache(cache *Cache) Client {
+	c.cache = cache
+	return c
+}
+
+// Clear removes everything from the cache.
+func (cache *Cache) Clear() error {
+	if err := os.RemoveAll(cache.dir); err != nil {
+		return fmt.Errorf("clearing cache failed: %s", err.Error())
+	}
+	return nil
+}
+
+// Stats goes through the cache and describes what's in it.
+func (cache *Cache) Stats() (CacheStats, error) {
+	var stats CacheStats
+	err := filepath.WalkDir(cache.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Entries++
+		stats.Size += info.Size()
+
+		entry, ok := readCacheEntry(path)
+		switch {
+		case ok && entry.validated():
+			stats.Validated++
+		case !ok || time.Since(entry.StoredOn) >= cache.ttl:
+			stats.Expired++
+		}
+		return nil
+	})
+	if err != nil {
+		return CacheStats{}, fmt.Errorf("reading cache failed: %s", err.Error())
+	}
+	return stats, nil
+}
+
+// tokenDir returns the directory the entries for the token are kept in.
+func (cache *Cache) tokenDir(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return filepath.Join(cache.dir, hex.EncodeToString(sum[:]))
+}
+
+// path returns the path of the entry for the URL requested with the token.
+func (cache *Cache) path(token, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(cache.tokenDir(token), hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the entry for the URL requested with the token, if there is one.
+func (cache *Cache) load(token, key string) (cacheEntry, bool) {
+	return readCacheEntry(cache.path(token, key))
+}
+
+// store saves the entry for the URL requested with the token. The cache is only an optimization, so failing to
+// save the entry is not an error. The entry is written to a temporary file first, so concurrent readers never see a
+// partially written one.
+func (cache *Cache) store(token, key string, entry cacheEntry) {
+	path := cache.path(token, key)
+	bs, err := json.Marshal(entry)
+	if err != nil || os.MkdirAll(filepath.Dir(path), 0700) != nil {
+		return
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(bs)
+	if closeErr := f.Close(); err != nil || closeErr != nil || os.Rename(f.Name(), path) != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// forget drops everything cached for the token.
+func (cache *Cache) forget(token string) {
+	os.RemoveAll(cache.tokenDir(token))
+}
+
+// readCacheEntry reads the entry at path. Entries that can't be read are treated as missing.
+func readCacheEntry(path string) (cacheEntry, bool) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(bs, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// validated reports whether the entry can be revalidated with a conditional request.
+func (e cacheEntry) validated() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+// response returns the entry as a response, as if it came from the API.
+func (e cacheEntry) response() *http.Response {
+	return &http.Response{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Header:     e.Header,
+		Body:       io.NopCloser(bytes.NewReader(e.Body)),
+	}
+}
+
+// volatilePaths are the resources of the API that change by themselves, as tasks run and transfers progress, rather
+// than only when the token holder changes them. A cached state of one of them can be stale long before the TTL.
+var volatilePaths = []string{"tasks", "storage/imports", "storage/exports"}
+
+// cacheable reports whether the response to a GET request to the URL can be cached. Only the responses of the API
+// are, the download links point elsewhere and the files behind them can be huge. Responses about the volatile
+// resources aren't either, so their state is always fresh.
+func (c Client) cacheable(u *url.URL) bool {
+	if !c.isAPI(u) {
+		return false
+	}
+	path := strings.TrimPrefix(u.String(), c.baseURL)
+	for _, volatile := range volatilePaths {
+		if path == volatile || strings.HasPrefix(path, volatile+"/") || strings.HasPrefix(path, volatile+"?") {
+			return false
+		}
+	}
+	return true
+}
+
+// isAPI reports whether the URL points to the API, rather than to a download link or the Datasets API.
+func (c Client) isAPI(u *url.URL) bool {
+	return strings.HasPrefix(u.String(), c.baseURL)
+}
+
+// readOnly reports whether the request only reads from the API. Besides GET requests, those are the bulk requests
+// getting the details of many resources at once, which are POSTed only because the IDs don't fit in a URL.
+func readOnly(method string, u *url.URL) bool {
+	return method == http.MethodGet || method == http.MethodPost && strings.HasSuffix(u.Path, "/get")
+}
+
+// cachedGet makes a GET request to the URL through the cache. Fresh entries without validators are returned without
+// asking the API, entries with validators are revalidated with a conditional request, and everything else is
+// requested and stored for the next time.
+func (c Client) cachedGet(u *url.URL) (*http.Response, error) {
+	key := u.String()
+	entry, ok := c.cache.load(c.token, key)
+	if ok && !entry.validated() && time.Since(entry.StoredOn) < c.cache.ttl {
+		return entry.response(), nil
+	}
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.StoredOn = time.Now()
+		c.cache.store(c.token, key, entry)
+		return entry.response(), nil
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response failed: %s", err.Error())
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(bs))
+
+	entry = cacheEntry{Header: make(http.Header), Body: bs, StoredOn: time.Now()}
+	for _, name := range cachedHeaders {
+		if val := resp.Header.Get(name); val != "" {
+			entry.Header.Set(name, val)
+		}
+	}
+	if entry.validated() || c.cache.ttl > 0 {
+		c.cache.store(c.token, key, entry)
+	}
+	return resp, nil
+}
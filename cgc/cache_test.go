@@ -0,0 +1,162 @@
+package cgc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// cacheServer serves a project, with or without validators, and counts the requests that made it to the API.
+type cacheServer struct {
+	etag        bool
+	fetched     int
+	notModified int
+}
+
+func (s *cacheServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Write([]byte(`{"items":[{"resource":{}}]}`))
+		return
+	}
+	s.fetched++
+	if s.etag {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			s.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+	}
+	w.Header().Set(totalHeader, "1")
+	w.Write([]byte(`{"id":"owner/project","name":"Project"}`))
+}
+
+func TestCache(t *testing.T) {
+	// between is the request made between the second and the third stat, task stats the task instead of the project
+	type in struct {
+		etag    bool
+		ttl     time.Duration
+		between string
+		task    bool
+	}
+	type out struct {
+		fetched     int
+		notModified int
+	}
+	td := []struct {
+		label string
+		in    in
+		out   out
+	}{
+		{"Revalidated with ETag", in{true, 0, "", false}, out{3, 2}},
+		{"Fresh without ETag", in{false, time.Hour, "", false}, out{1, 0}},
+		{"Expired without ETag", in{false, 0, "", false}, out{3, 0}},
+		{"Dropped after a change", in{false, time.Hour, "update", false}, out{2, 0}},
+		{"Kept after a bulk get", in{false, time.Hour, "bulk get", false}, out{1, 0}},
+		{"Tasks never cached", in{true, time.Hour, "", true}, out{3, 0}},
+	}
+
+	testToken := "test_token"
+	contentType := "application/json"
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			s := &cacheServer{etag: tt.in.etag}
+			ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, s.handle)))
+			defer ts.Close()
+			cache := NewCache(t.TempDir(), tt.in.ttl)
+			client := New(testToken).WithCache(cache)
+			client.baseURL = ts.URL + "/"
+
+			for i := 0; i < 3; i++ {
+				if tt.in.task {
+					if _, err := client.StatTask("task"); err != nil {
+						t.Fatalf("expected no error, got '%v'", err)
+					}
+				} else {
+					project, err := client.StatProject("owner/project")
+					if err != nil {
+						t.Fatalf("expected no error, got '%v'", err)
+					}
+					if project.Name != "Project" {
+						t.Fatalf("expected the project, got %+v", project)
+					}
+				}
+
+				if i != 1 {
+					continue
+				}
+				var err error
+				switch tt.in.between {
+				case "update":
+					_, err = client.UpdateProject("owner/project", []string{"name=Project"})
+				case "bulk get":
+					_, err = client.StatImports([]string{"import"})
+				}
+				if err != nil {
+					t.Fatalf("expected no error, got '%v'", err)
+				}
+			}
+			if s.fetched != tt.out.fetched || s.notModified != tt.out.notModified {
+				t.Fatalf("expected %d requests, %d not modified, got %d and %d", tt.out.fetched, tt.out.notModified,
+					s.fetched, s.notModified)
+			}
+		})
+	}
+}
+
+func TestCacheTokens(t *testing.T) {
+	s := &cacheServer{}
+	ts := httptest.NewServer(http.HandlerFunc(s.handle))
+	defer ts.Close()
+	cache := NewCache(t.TempDir(), time.Hour)
+
+	for _, token := range []string{"first", "second", "first"} {
+		client := New(token).WithCache(cache)
+		client.baseURL = ts.URL + "/"
+		if _, err := client.StatProject("owner/project"); err != nil {
+			t.Fatalf("expected no error, got '%v'", err)
+		}
+	}
+	if s.fetched != 2 {
+		t.Fatalf("expected every token to have its own entries, got %d requests", s.fetched)
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if stats.Entries != 2 || stats.Size == 0 || stats.Validated != 0 || stats.Expired != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if stats, err := cache.Stats(); err != nil || stats.Entries != 0 {
+		t.Fatalf("expected an empty cache, got %+v, '%v'", stats, err)
+	}
+}
+
+func TestCacheIterator(t *testing.T) {
+	s := &cacheServer{etag: true}
+	ts := httptest.NewServer(http.HandlerFunc(s.handle))
+	defer ts.Close()
+	client := New("token").WithCache(NewCache(t.TempDir(), 0))
+	client.baseURL = ts.URL + "/"
+
+	for i := 0; i < 2; i++ {
+		u := mustParseURL(client.baseURL)
+		u.Path += "projects"
+		it := newIterator[Project](client, u, ListOptions{})
+		for it.Next() {
+		}
+		if total, ok := it.Total(); it.Err() != nil || !ok || total != 1 {
+			t.Fatalf("expected the total to be kept with the page, got %d, %v, '%v'", total, ok, it.Err())
+		}
+	}
+	if s.notModified != 1 {
+		t.Fatalf("expected the second page to be revalidated, got %d not modified", s.notModified)
+	}
+}
@@ -2,6 +2,7 @@
 package cgc
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,14 +10,26 @@ import (
 )
 
 const (
-	tokenHeader = "X-SBG-Auth-Token"
-	baseURL     = "https://cgc-api.sbgenomics.com/v2/"
+	tokenHeader     = "X-SBG-Auth-Token"
+	baseURL         = "https://cgc-api.sbgenomics.com/v2/"
+	datasetsBaseURL = "https://cgc-datasets-api.sbgenomics.com/"
 )
 
 type apiErrorResponseTemplate struct {
 	Message string `json:"message"`
 }
 
+// StatusError is the error returned when the API responds with a status code other than 2xx, so the callers can
+// tell apart the errors that are worth retrying (e.g. 5xx) from the ones that aren't (e.g. 401 or 404).
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status code: %d, message: %s", e.StatusCode, e.Message)
+}
+
 type apiOKResponseTemplate struct {
 	Href  string `json:"href"`
 	Links []struct {
@@ -28,42 +41,93 @@ type apiOKResponseTemplate struct {
 
 // Client struct is the client that is holding the necessary information used in every request made to
 // the CGC API (e.g. the token and the baseURL). Base URL is a field of this struct so the mocking process,
-// used when testing, is easier.
+// used when testing, is easier. The Datasets API is served from a separate host, which is kept in datasetsURL.
+// Responses are cached only if a cache is set with WithCache.
 type Client struct {
-	token      string
-	httpClient *http.Client
-	baseURL    string
+	token       string
+	httpClient  *http.Client
+	baseURL     string
+	datasetsURL string
+	cache       *Cache
 }
 
 // New returns an initialized CGC client.
 func New(token string) Client {
 	return Client{
-		token:      token,
-		httpClient: http.DefaultClient,
-		baseURL:    baseURL,
+		token:       token,
+		httpClient:  http.DefaultClient,
+		baseURL:     baseURL,
+		datasetsURL: datasetsBaseURL,
 	}
 }
 
 // request makes a HTTP request to provided url using a given method and body. This is a convenience
 // method that sets all the specific headers that are used in every request, like the authorization header.
-// Returns the response body if all went okay, else decodes the error message from the API and returns it as
-// an error.
+// Returns the response body if the API responded with a 2xx status code, else decodes the error message from the API
+// and returns it as an error.
 func (c Client) request(method string, u *url.URL, body io.Reader) (io.ReadCloser, error) {
+	resp, err := c.do(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do is the same as request, but returns the whole response so the headers can be inspected as well. The caller
+// is responsible for closing the response body. If the client has a cache, GET requests to the API go through it,
+// and any request that isn't read only drops everything cached for the token.
+func (c Client) do(method string, u *url.URL, body io.Reader) (*http.Response, error) {
+	if c.cache != nil && method == http.MethodGet && c.cacheable(u) {
+		return c.cachedGet(u)
+	}
+
+	req, err := c.newRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil && !readOnly(method, u) && c.isAPI(u) {
+		c.cache.forget(c.token)
+	}
+	return resp, nil
+}
+
+// newRequest creates a request with the headers that are used in every request, like the authorization header.
+func (c Client) newRequest(method string, u *url.URL, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequest(method, u.String(), body)
 	if err != nil {
 		return nil, fmt.Errorf("creating request failed: %s", err.Error())
 	}
 	req.Header.Add(tokenHeader, c.token)
 	req.Header.Add("Content-Type", "application/json")
+	return req, nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %s", err.Error())
+// checkStatus returns the error message from the API as an error if the response doesn't have a 2xx status code,
+// in which case the response body is closed.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return &StatusError{StatusCode: resp.StatusCode, Message: decodeError(resp.Body).Error()}
 	}
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status code: %d, message: %s", resp.StatusCode, decodeError(resp.Body))
+// transient reports whether the request failed for a reason that may go away by itself, like a network error or a
+// 5xx (or 429) status code, so it's worth making the same request again.
+func transient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
 	}
-
-	return resp.Body, nil
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
 }
@@ -39,7 +39,7 @@ func contentTypeMiddleware(contentType string, f http.HandlerFunc) http.HandlerF
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		if r.Method != http.MethodGet && (r.Header.Get("Content-Type") != contentType || !json.Valid(bs)) {
+		if r.Method != http.MethodGet && r.Method != http.MethodDelete && (r.Header.Get("Content-Type") != contentType || !json.Valid(bs)) {
 			w.WriteHeader(http.StatusUnsupportedMediaType)
 			x := apiErrorResponseTemplate{
 				Message: "Unsupported Media Type.",
@@ -0,0 +1,121 @@
+package cgc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// copyStatusOK is the status the bulk copy endpoint reports for every file that got copied.
+const copyStatusOK = "OK"
+
+// FileCopyResult is the outcome of copying a single file out of a bulk request. NewFileID and NewFileName describe
+// the copy made in the destination project.
+type FileCopyResult struct {
+	FileID      string
+	NewFileID   string
+	NewFileName string
+	Err         error
+}
+
+// StatFiles gets the details of all the files with the given IDs using bulk requests. A file that couldn't be
+// fetched (e.g. one the token holder can't read) doesn't stop the others, the outcome for each one is returned in the
+// same order the IDs were given in.
+func (c Client) StatFiles(fileIDs []string) []FileActionResult {
+	items := bulkRequest[string, File](c, "bulk/files/get", "file_ids", fileIDs)
+	results := make([]FileActionResult, 0, len(fileIDs))
+	for i, item := range items {
+		results = append(results, FileActionResult{fileIDs[i], item.Resource, item.err()})
+	}
+	return results
+}
+
+// CopyFiles copies the files with the given IDs into the project with projectID, splitting them into as many bulk
+// requests as needed. A file that couldn't be copied doesn't stop the others, the outcome for each one is returned in
+// the same order the IDs were given in. The files of a bulk request that failed as a whole are returned with the
+// error of that request.
+func (c Client) CopyFiles(fileIDs []string, projectID string) []FileCopyResult {
+	results := make([]FileCopyResult, 0, len(fileIDs))
+	inChunks(len(fileIDs), bulkMaxItems, func(start, end int) {
+		copied, err := c.copyFiles(fileIDs[start:end], projectID)
+		if err != nil {
+			for _, id := range fileIDs[start:end] {
+				copied = append(copied, FileCopyResult{FileID: id, Err: err})
+			}
+		}
+		results = append(results, copied...)
+	})
+	return results
+}
+
+// SkipExisting splits the files into the ones missing from the project with projectID and the ones that are already
+// there, that is the ones with the same name as one of the files in the project.
+func (c Client) SkipExisting(files []File, projectID string) ([]File, []File, error) {
+	existing, err := c.Files(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[string]bool, len(existing))
+	for _, file := range existing {
+		names[file.Name] = true
+	}
+
+	missing := make([]File, 0, len(files))
+	present := make([]File, 0)
+	for _, file := range files {
+		if names[file.Name] {
+			present = append(present, file)
+		} else {
+			missing = append(missing, file)
+		}
+	}
+	return missing, present, nil
+}
+
+// copyFiles copies a single batch of files, small enough to fit a single request.
+func (c Client) copyFiles(fileIDs []string, projectID string) ([]FileCopyResult, error) {
+	encoded, err := json.Marshal(map[string]interface{}{
+		"file_ids": fileIDs,
+		"project":  projectID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding request failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += "action/files/copy"
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	// the response is keyed by the IDs of the files being copied
+	var r map[string]struct {
+		Status      string `json:"status"`
+		Message     string `json:"message"`
+		NewFileID   string `json:"new_file_id"`
+		NewFileName string `json:"new_file_name"`
+	}
+	if err := json.NewDecoder(resp).Decode(&r); err != nil {
+		return nil, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	results := make([]FileCopyResult, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		result := FileCopyResult{FileID: id}
+		copied, ok := r[id]
+		switch {
+		case !ok:
+			result.Err = fmt.Errorf("missing from the response")
+		case copied.Status != copyStatusOK:
+			result.Err = fmt.Errorf("%s", copied.Message)
+		default:
+			result.NewFileID, result.NewFileName = copied.NewFileID, copied.NewFileName
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,176 @@
+package cgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func handleCopy(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/files":
+		var resp struct {
+			apiOKResponseTemplate
+			Items []File `json:"items"`
+		}
+		resp.Items = []File{{ID: "copy-a", Name: "a.bam"}}
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/bulk/files/get":
+		var req struct {
+			FileIDs []string `json:"file_ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.FileIDs) > bulkMaxItems {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if contains(req.FileIDs, "unavailable") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var resp struct {
+			Items []bulkItem[File] `json:"items"`
+		}
+		for _, id := range req.FileIDs {
+			if id == "restricted" {
+				resp.Items = append(resp.Items, bulkItem[File]{Error: &apiErrorResponseTemplate{"access denied"}})
+				continue
+			}
+			resp.Items = append(resp.Items, bulkItem[File]{Resource: File{ID: id, Name: id + ".bam"}})
+		}
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/action/files/copy":
+		var req struct {
+			FileIDs []string `json:"file_ids"`
+			Project string   `json:"project"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.FileIDs) > bulkMaxItems {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if contains(req.FileIDs, "unavailable") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		resp := make(map[string]map[string]string)
+		for _, id := range req.FileIDs {
+			if id == "restricted" {
+				resp[id] = map[string]string{"status": "FORBIDDEN", "message": "access denied"}
+				continue
+			}
+			resp[id] = map[string]string{"status": copyStatusOK, "new_file_id": req.Project + "-" + id}
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestCopyFiles(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleCopy)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	ids := make([]string, 0)
+	for i := 0; i < bulkMaxItems+50; i++ {
+		ids = append(ids, "public")
+	}
+	ids[bulkMaxItems+1] = "restricted"
+
+	results := client.CopyFiles(ids, "cohort")
+	if len(results) != len(ids) {
+		t.Fatalf("expected %d results, got %d", len(ids), len(results))
+	}
+	for i, r := range results {
+		if i == bulkMaxItems+1 {
+			if r.Err == nil || r.Err.Error() != "access denied" {
+				t.Fatalf("expected copying a restricted file to fail, got %+v", r)
+			}
+			continue
+		}
+		if r.Err != nil || r.NewFileID != "cohort-public" {
+			t.Fatalf("expected file to be copied, got %+v", r)
+		}
+	}
+	ids[bulkMaxItems+1] = "unavailable"
+	results = client.CopyFiles(ids, "cohort")
+	if len(results) != len(ids) {
+		t.Fatalf("expected %d results, got %d", len(ids), len(results))
+	}
+	for i, r := range results {
+		if failed := i >= bulkMaxItems; failed != (r.Err != nil) {
+			t.Fatalf("expected only the files of the failed request to have an error, got %+v for file %d", r, i)
+		}
+		if i < bulkMaxItems && r.NewFileID != "cohort-public" {
+			t.Fatalf("expected file to be copied, got %+v", r)
+		}
+	}
+}
+
+func TestSkipExisting(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleCopy)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	files := []File{{ID: "1", Name: "a.bam"}, {ID: "2", Name: "b.bam"}}
+	missing, present, err := client.SkipExisting(files, "cohort")
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if len(missing) != 1 || missing[0].ID != "2" {
+		t.Fatalf("expected only 'b.bam' to be missing, got %+v", missing)
+	}
+	if len(present) != 1 || present[0].ID != "1" {
+		t.Fatalf("expected 'a.bam' to be present, got %+v", present)
+	}
+}
+
+func TestStatFiles(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleCopy)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	results := client.StatFiles([]string{"a", "restricted", "b"})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].File.Name != "a.bam" || results[2].Err != nil || results[2].FileID != "b" {
+		t.Fatalf("expected 'a' and 'b' to be fetched, got %+v", results)
+	}
+	if results[1].FileID != "restricted" || results[1].Err == nil {
+		t.Fatalf("expected 'restricted' to fail, got %+v", results[1])
+	}
+	ids := make([]string, bulkMaxItems+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("file-%d", i)
+	}
+	ids[0] = "unavailable"
+	results = client.StatFiles(ids)
+	if len(results) != len(ids) {
+		t.Fatalf("expected %d results, got %d", len(ids), len(results))
+	}
+	for i, r := range results {
+		if failed := i < bulkMaxItems; failed != (r.Err != nil) {
+			t.Fatalf("expected only the files of the failed request to have an error, got %+v for file %d", r, i)
+		}
+	}
+	if results[bulkMaxItems].File.Name != ids[bulkMaxItems]+".bam" {
+		t.Fatalf("expected the last file to be fetched, got %+v", results[bulkMaxItems])
+	}
+}
@@ -0,0 +1,432 @@
+package cgc
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// CWLType is a single type a CWL input accepts. Items is set for arrays and holds the type of the array items,
+// while Symbols is set for enums and holds the allowed values.
+type CWLType struct {
+	Kind    string
+	Items   *CWLType
+	Symbols []string
+}
+
+func (t CWLType) String() string {
+	if t.Kind == "array" && t.Items != nil {
+		return t.Items.String() + "[]"
+	}
+	return t.Kind
+}
+
+// CWLInput is an input port of a CWL app. Types holds all the types the input accepts, apart from null, which is
+// accepted only if the input is not Required. SecondaryFiles holds the patterns of the files that have to be
+// present next to the files passed to the input.
+type CWLInput struct {
+	ID             string
+	Types          []CWLType
+	Required       bool
+	SecondaryFiles []string
+}
+
+// InputProblem describes a single issue found while validating task inputs.
+type InputProblem struct {
+	Port    string
+	Message string
+}
+
+func (p InputProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Port, p.Message)
+}
+
+// ParseCWLInputs parses the input ports out of the CWL description of an app, as returned by RawApp. Inputs can be
+// described both as a list and as a map keyed by the input ID.
+func ParseCWLInputs(raw map[string]interface{}) ([]CWLInput, error) {
+	var defs []map[string]interface{}
+	switch inputs := raw["inputs"].(type) {
+	case []interface{}:
+		for _, i := range inputs {
+			def, ok := i.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("malformed input '%v'", i)
+			}
+			defs = append(defs, def)
+		}
+	case map[string]interface{}:
+		for id, i := range inputs {
+			def, ok := i.(map[string]interface{})
+			if !ok {
+				// shorthand where only the type is given
+				def = map[string]interface{}{"type": i}
+			}
+			def["id"] = id
+			defs = append(defs, def)
+		}
+	case nil:
+	default:
+		return nil, fmt.Errorf("malformed inputs")
+	}
+
+	inputs := make([]CWLInput, 0, len(defs))
+	for _, def := range defs {
+		id, _ := def["id"].(string)
+		input := CWLInput{ID: cwlName(id), Required: true}
+		for _, t := range cwlTypes(def["type"]) {
+			if t.Kind == "null" {
+				input.Required = false
+			} else {
+				input.Types = append(input.Types, t)
+			}
+		}
+		if _, ok := def["default"]; ok {
+			input.Required = false
+		}
+		input.SecondaryFiles = cwlPatterns(def["secondaryFiles"])
+		inputs = append(inputs, input)
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].ID < inputs[j].ID })
+
+	return inputs, nil
+}
+
+// ValidateInputs checks the task inputs against the input ports of an app. Inputs on unknown ports, missing required
+// inputs and values that don't fit the type of the port (e.g. a single file passed where an array of files is
+// expected, or a value that's not one of the enum symbols) are reported. The batch input of a batch task is allowed
+// to hold an array of files even if the port accepts a single one. Problems are sorted by port.
+func ValidateInputs(ports []CWLInput, inputs map[string]interface{}, batchInput string) []InputProblem {
+	problems := make([]InputProblem, 0)
+	known := make(map[string]bool)
+	for _, port := range ports {
+		known[port.ID] = true
+		value, ok := inputs[port.ID]
+		if !ok || value == nil {
+			if port.Required {
+				problems = append(problems, InputProblem{port.ID, "required input is missing"})
+			}
+			continue
+		}
+
+		if len(port.Types) == 0 {
+			continue
+		}
+		var msg string
+		for _, t := range portTypes(port, batchInput) {
+			if msg = checkCWLValue(t, value); msg == "" {
+				break
+			}
+		}
+		if msg != "" {
+			problems = append(problems, InputProblem{port.ID, msg})
+		}
+	}
+
+	for id := range inputs {
+		if !known[id] {
+			problems = append(problems, InputProblem{id, "not an input of the app"})
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Port < problems[j].Port })
+
+	return problems
+}
+
+// ShapeInputs wraps a single value given to a port that only takes arrays (e.g. a lone file passed to a File[]
+// port) into an array of one item, so the inputs have the shape the app expects. Whether a port takes an array is
+// decided by its type, the same way ValidateInputs decides it. Values that wouldn't fit the port even when wrapped
+// are left as they are for ValidateInputs to report.
+func ShapeInputs(ports []CWLInput, inputs map[string]interface{}, batchInput string) {
+	for _, port := range ports {
+		value, ok := inputs[port.ID]
+		if _, isArray := value.([]interface{}); !ok || value == nil || isArray {
+			continue
+		}
+		types := portTypes(port, batchInput)
+		fits, fitsWrapped := false, false
+		for _, t := range types {
+			fits = fits || checkCWLValue(t, value) == ""
+			fitsWrapped = fitsWrapped || checkCWLValue(t, []interface{}{value}) == ""
+		}
+		if !fits && fitsWrapped {
+			inputs[port.ID] = []interface{}{value}
+		}
+	}
+}
+
+// portTypes returns the types the port accepts. The batch input of a batch task holds an array of the values the
+// port accepts.
+func portTypes(port CWLInput, batchInput string) []CWLType {
+	if port.ID != batchInput {
+		return port.Types
+	}
+	types := make([]CWLType, 0, len(port.Types))
+	for _, t := range port.Types {
+		if t.Kind != "array" {
+			item := t
+			t = CWLType{Kind: "array", Items: &item}
+		}
+		types = append(types, t)
+	}
+	return types
+}
+
+// ValidateTaskInputs validates the inputs of the task against its app, fetching the CWL description of the app.
+// On top of what ValidateInputs checks, secondary files required by the ports are looked up in the task project.
+// The inputs of the task are shaped with ShapeInputs before they're validated, so a single file given to an array
+// port is turned into an array in task.Inputs.
+func (c Client) ValidateTaskInputs(task Task) ([]InputProblem, error) {
+	raw, err := c.RawApp(task.App, LatestRevision)
+	if err != nil {
+		return nil, err
+	}
+	ports, err := ParseCWLInputs(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing inputs of app '%s' failed: %s", task.App, err.Error())
+	}
+
+	ShapeInputs(ports, task.Inputs, task.BatchInput)
+	problems := ValidateInputs(ports, task.Inputs, task.BatchInput)
+	for _, port := range ports {
+		if len(port.SecondaryFiles) == 0 {
+			continue
+		}
+		for _, fileID := range inputFileIDs(task.Inputs[port.ID]) {
+			missing, err := c.missingSecondaryFiles(task.Project, fileID, port.SecondaryFiles)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range missing {
+				msg := fmt.Sprintf("secondary file '%s' is missing", name)
+				problems = append(problems, InputProblem{port.ID, msg})
+			}
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Port < problems[j].Port })
+
+	return problems, nil
+}
+
+// InputError returns an error describing all the problems, or nil if there are none.
+func InputError(problems []InputProblem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(problems))
+	for _, p := range problems {
+		msgs = append(msgs, p.String())
+	}
+	return fmt.Errorf("invalid task inputs: %s", strings.Join(msgs, "; "))
+}
+
+// missingSecondaryFiles returns the names of the secondary files of the file with fileID that are not present in
+// the project with projectID. Secondary files are looked for next to the file, in the same folder.
+func (c Client) missingSecondaryFiles(projectID, fileID string, patterns []string) ([]string, error) {
+	file, err := c.StatFile(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	// files in folders get the whole folder listed once, files at the root of the project are looked up by name
+	var siblings map[string]bool
+	if file.Parent != "" {
+		siblings = make(map[string]bool)
+		it := c.IterateFolder(file.Parent, ListOptions{})
+		for it.Next() {
+			siblings[it.Item().Name] = true
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	missing := make([]string, 0)
+	for _, pattern := range patterns {
+		name := SecondaryFileName(file.Name, pattern)
+		if name == "" {
+			continue
+		}
+		if siblings != nil {
+			if !siblings[name] {
+				missing = append(missing, name)
+			}
+			continue
+		}
+		found, err := c.FilterFiles(projectID, FileFilter{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing, nil
+}
+
+// SecondaryFileName returns the name of the secondary file of the file with the given name, following the CWL
+// rules: every leading '^' removes an extension from the name before the rest of the pattern is appended. Returns
+// an empty string for patterns that are expressions, since those can't be evaluated locally.
+func SecondaryFileName(name, pattern string) string {
+	if strings.Contains(pattern, "$(") || strings.Contains(pattern, "${") {
+		return ""
+	}
+	for strings.HasPrefix(pattern, "^") {
+		pattern = pattern[1:]
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	return name + pattern
+}
+
+// checkCWLValue returns a message describing why value doesn't fit the type, or an empty string if it does.
+func checkCWLValue(t CWLType, value interface{}) string {
+	switch t.Kind {
+	case "File", "Directory":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			if _, isArray := value.([]interface{}); isArray {
+				return fmt.Sprintf("expected a single %s, got an array", t.Kind)
+			}
+			return fmt.Sprintf("expected a %s, got '%v'", t.Kind, value)
+		}
+		if class, ok := obj["class"]; ok && class != t.Kind {
+			return fmt.Sprintf("expected a %s, got a %v", t.Kind, class)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Sprintf("expected an array of %s, got a single value", t.Items)
+		}
+		if t.Items == nil {
+			return ""
+		}
+		for _, item := range items {
+			if msg := checkCWLValue(*t.Items, item); msg != "" {
+				return msg
+			}
+		}
+	case "enum":
+		s, _ := value.(string)
+		for _, symbol := range t.Symbols {
+			if s == symbol {
+				return ""
+			}
+		}
+		return fmt.Sprintf("'%v' is not one of: %s", value, strings.Join(t.Symbols, ", "))
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf("expected a string, got '%v'", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("expected a boolean, got '%v'", value)
+		}
+	case "int", "long", "float", "double":
+		switch value.(type) {
+		case float64, int, int64:
+		default:
+			return fmt.Sprintf("expected a number, got '%v'", value)
+		}
+	}
+	return ""
+}
+
+// cwlTypes parses a CWL type definition into all the types it accepts, including null for optional types.
+func cwlTypes(def interface{}) []CWLType {
+	switch d := def.(type) {
+	case string:
+		if strings.HasSuffix(d, "?") {
+			return append(cwlTypes(strings.TrimSuffix(d, "?")), CWLType{Kind: "null"})
+		}
+		if strings.HasSuffix(d, "[]") {
+			items := cwlType(strings.TrimSuffix(d, "[]"))
+			return []CWLType{{Kind: "array", Items: &items}}
+		}
+		return []CWLType{{Kind: d}}
+	case []interface{}:
+		types := make([]CWLType, 0, len(d))
+		for _, t := range d {
+			types = append(types, cwlTypes(t)...)
+		}
+		return types
+	case map[string]interface{}:
+		kind, _ := d["type"].(string)
+		t := CWLType{Kind: kind}
+		switch kind {
+		case "array":
+			items := cwlType(d["items"])
+			t.Items = &items
+		case "enum":
+			symbols, _ := d["symbols"].([]interface{})
+			for _, s := range symbols {
+				if symbol, ok := s.(string); ok {
+					t.Symbols = append(t.Symbols, cwlName(symbol))
+				}
+			}
+		}
+		return []CWLType{t}
+	}
+	return nil
+}
+
+// cwlType parses a CWL type definition that's expected to hold a single type.
+func cwlType(def interface{}) CWLType {
+	types := cwlTypes(def)
+	for _, t := range types {
+		if t.Kind != "null" {
+			return t
+		}
+	}
+	return CWLType{}
+}
+
+// cwlPatterns parses the secondary files patterns, given either as strings or as objects with the pattern field.
+func cwlPatterns(def interface{}) []string {
+	var defs []interface{}
+	switch d := def.(type) {
+	case []interface{}:
+		defs = d
+	case nil:
+		return nil
+	default:
+		defs = []interface{}{d}
+	}
+
+	patterns := make([]string, 0, len(defs))
+	for _, d := range defs {
+		switch p := d.(type) {
+		case string:
+			patterns = append(patterns, p)
+		case map[string]interface{}:
+			if pattern, ok := p["pattern"].(string); ok {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
+
+// cwlName strips the prefix CWL identifiers can have (e.g. '#reads' or 'file.cwl#port/symbol') off the name.
+func cwlName(id string) string {
+	if i := strings.LastIndexAny(id, "#/"); i >= 0 {
+		return id[i+1:]
+	}
+	return id
+}
+
+// inputFileIDs returns the IDs of all the files referenced by an input value.
+func inputFileIDs(value interface{}) []string {
+	ids := make([]string, 0)
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			ids = append(ids, inputFileIDs(item)...)
+		}
+	case map[string]interface{}:
+		if id, ok := v["path"].(string); ok && v["class"] != "Directory" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,274 @@
+package cgc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testCWL = `{
+	"class": "Workflow",
+	"inputs": [
+		{"id": "#reads", "type": "File[]"},
+		{"id": "#reference", "type": ["null", "File"], "secondaryFiles": [".fai", "^.dict"]},
+		{"id": "#bam", "type": "File", "secondaryFiles": [{"pattern": ".bai"}]},
+		{"id": "#mode", "type": ["null", {"type": "enum", "name": "mode", "symbols": ["#mode/fast", "#mode/slow"]}]},
+		{"id": "#threads", "type": "int", "default": 4}
+	]
+}`
+
+func TestParseCWLInputs(t *testing.T) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(testCWL), &raw); err != nil {
+		t.Fatalf("unmarshalling CWL failed: %s", err.Error())
+	}
+
+	ports, err := ParseCWLInputs(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+
+	byID := make(map[string]CWLInput)
+	for _, p := range ports {
+		byID[p.ID] = p
+	}
+	if len(byID) != 5 {
+		t.Fatalf("expected 5 inputs, got %d", len(byID))
+	}
+	if !byID["reads"].Required || byID["reads"].Types[0].String() != "File[]" {
+		t.Fatalf("expected required 'File[]' reads, got %+v", byID["reads"])
+	}
+	if byID["reference"].Required || strings.Join(byID["reference"].SecondaryFiles, ",") != ".fai,^.dict" {
+		t.Fatalf("expected optional reference with secondary files, got %+v", byID["reference"])
+	}
+	if byID["threads"].Required {
+		t.Fatalf("expected input with default to be optional")
+	}
+	if strings.Join(byID["mode"].Types[0].Symbols, ",") != "fast,slow" {
+		t.Fatalf("expected 'fast,slow' symbols, got %v", byID["mode"].Types[0].Symbols)
+	}
+}
+
+func TestValidateInputs(t *testing.T) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(testCWL), &raw); err != nil {
+		t.Fatalf("unmarshalling CWL failed: %s", err.Error())
+	}
+	ports, err := ParseCWLInputs(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+
+	type in struct {
+		inputs     map[string]interface{}
+		batchInput string
+	}
+	td := []struct {
+		label    string
+		in       in
+		problems string
+	}{
+		{
+			"All good",
+			in{map[string]interface{}{
+				"reads": []interface{}{FileInput("a")},
+				"bam":   FileInput("b"),
+				"mode":  "fast",
+			}, ""},
+			"",
+		},
+		{
+			"Missing and unknown",
+			in{map[string]interface{}{"reads": []interface{}{}, "foo": "bar"}, ""},
+			"bam: required input is missing,foo: not an input of the app",
+		},
+		{
+			"File instead of array",
+			in{map[string]interface{}{"reads": FileInput("a"), "bam": FileInput("b")}, ""},
+			"reads: expected an array of File, got a single value",
+		},
+		{
+			"Array instead of file",
+			in{map[string]interface{}{"reads": []interface{}{}, "bam": []interface{}{FileInput("b")}}, ""},
+			"bam: expected a single File, got an array",
+		},
+		{
+			"Batch input",
+			in{map[string]interface{}{"reads": []interface{}{}, "bam": []interface{}{FileInput("b")}}, "bam"},
+			"",
+		},
+		{
+			"Invalid enum",
+			in{map[string]interface{}{"reads": []interface{}{}, "bam": FileInput("b"), "mode": "medium"}, ""},
+			"mode: 'medium' is not one of: fast, slow",
+		},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			problems := ValidateInputs(ports, tt.in.inputs, tt.in.batchInput)
+			msgs := make([]string, 0, len(problems))
+			for _, p := range problems {
+				msgs = append(msgs, p.String())
+			}
+			if strings.Join(msgs, ",") != tt.problems {
+				t.Fatalf("expected '%s', got '%s'", tt.problems, strings.Join(msgs, ","))
+			}
+		})
+	}
+}
+
+func TestShapeInputs(t *testing.T) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(testCWL), &raw); err != nil {
+		t.Fatalf("unmarshalling CWL failed: %s", err.Error())
+	}
+	ports, err := ParseCWLInputs(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+
+	td := []struct {
+		label      string
+		inputs     map[string]interface{}
+		batchInput string
+		wrapped    string
+	}{
+		{"One file to an array", map[string]interface{}{"reads": FileInput("a"), "bam": FileInput("b")}, "", "reads"},
+		{"Array stays", map[string]interface{}{"reads": []interface{}{FileInput("a")}, "bam": FileInput("b")}, "", ""},
+		{"One file to the batch input", map[string]interface{}{"reads": []interface{}{}, "bam": FileInput("b")},
+			"bam", "bam"},
+		{"Not a file", map[string]interface{}{"reads": "a", "bam": FileInput("b")}, "", ""},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			before := make(map[string]interface{})
+			for k, v := range tt.inputs {
+				before[k] = v
+			}
+			ShapeInputs(ports, tt.inputs, tt.batchInput)
+			for port, value := range tt.inputs {
+				_, wasArray := before[port].([]interface{})
+				items, isArray := value.([]interface{})
+				switch {
+				case port == tt.wrapped && (!isArray || len(items) != 1 || items[0] == nil):
+					t.Fatalf("expected '%s' to be wrapped into an array, got %v", port, value)
+				case port != tt.wrapped && isArray != wasArray:
+					t.Fatalf("expected '%s' to stay as it was, got %v", port, value)
+				}
+			}
+			if tt.wrapped != "" {
+				if problems := ValidateInputs(ports, tt.inputs, tt.batchInput); len(problems) != 0 {
+					t.Fatalf("expected no problems, got %v", problems)
+				}
+			}
+		})
+	}
+}
+
+// secondaryFiles are the files served by handleSecondaryFiles, 'folder' holds a BAM with its index and one without.
+var secondaryFiles = map[string]File{
+	"f1": {ID: "f1", Name: "a.bam", Parent: "folder"},
+	"f2": {ID: "f2", Name: "a.bam.bai", Parent: "folder"},
+	"f3": {ID: "f3", Name: "b.bam", Parent: "folder"},
+	"f4": {ID: "f4", Name: "c.bam"},
+	"f5": {ID: "f5", Name: "c.bam.bai"},
+	"f6": {ID: "f6", Name: "d.bam"},
+	"f7": {ID: "f7", Name: "b.bam.bai"},
+}
+
+func handleSecondaryFiles(w http.ResponseWriter, r *http.Request) {
+	var resp interface{}
+	switch {
+	case r.URL.Path == "/apps/owner/project/app/raw":
+		w.Write([]byte(testCWL))
+		return
+	case r.URL.Path == "/files/folder/list" || r.URL.Path == "/files":
+		parent := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/files/"), "/list")
+		if r.URL.Path == "/files" {
+			parent = ""
+		}
+		items := make([]File, 0)
+		for _, f := range secondaryFiles {
+			if name := r.URL.Query().Get("name"); f.Parent == parent && (name == "" || name == f.Name) {
+				items = append(items, f)
+			}
+		}
+		resp = map[string][]File{"items": items}
+	case strings.HasPrefix(r.URL.Path, "/files/"):
+		f, ok := secondaryFiles[strings.TrimPrefix(r.URL.Path, "/files/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resp = f
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestValidateTaskInputsSecondaryFiles(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleSecondaryFiles)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	td := []struct {
+		label    string
+		bam      string
+		problems string
+	}{
+		{"Index next to the file in a folder", "f1", ""},
+		{"Index missing from the folder", "f3", "bam: secondary file 'b.bam.bai' is missing"},
+		{"Index next to the file at the root", "f4", ""},
+		{"Index missing from the root", "f6", "bam: secondary file 'd.bam.bai' is missing"},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			problems, err := client.ValidateTaskInputs(Task{
+				Project: "owner/project",
+				App:     "owner/project/app",
+				Inputs:  map[string]interface{}{"reads": []interface{}{}, "bam": FileInput(tt.bam)},
+			})
+			if err != nil {
+				t.Fatalf("expected no error, got '%v'", err)
+			}
+			msgs := make([]string, 0, len(problems))
+			for _, p := range problems {
+				msgs = append(msgs, p.String())
+			}
+			if strings.Join(msgs, ",") != tt.problems {
+				t.Fatalf("expected '%s', got '%s'", tt.problems, strings.Join(msgs, ","))
+			}
+		})
+	}
+}
+
+func TestSecondaryFileName(t *testing.T) {
+	td := []struct {
+		name     string
+		pattern  string
+		expected string
+	}{
+		{"a.bam", ".bai", "a.bam.bai"},
+		{"a.bam", "^.bai", "a.bai"},
+		{"a.vcf.gz", "^^.idx", "a.idx"},
+		{"a.bam", "$(self.basename).bai", ""},
+	}
+
+	for _, tt := range td {
+		if name := SecondaryFileName(tt.name, tt.pattern); name != tt.expected {
+			t.Fatalf("expected '%s', got '%s'", tt.expected, name)
+		}
+	}
+}
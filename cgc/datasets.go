@@ -0,0 +1,172 @@
+package cgc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Entities of a dataset that can be queried.
+const (
+	DatasetFiles   = "files"
+	DatasetCases   = "cases"
+	DatasetSamples = "samples"
+)
+
+// Dataset struct represents the dataset information returned from the Datasets API.
+type Dataset struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// DatasetEntity struct represents a single entity (e.g. a file or a case) matching a dataset query. IDs of file
+// entities are the IDs of the files on the platform.
+type DatasetEntity struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// DatasetQuery is a structured query over the entities of a dataset, in the format the Datasets API expects. The
+// 'entity' key holds the entity being queried, while the rest of the keys are the filters, which can be nested to
+// filter by related entities, e.g. {"entity": "files", "hasDataFormat": "BAM", "hasCase": {"hasGender": "Female"}}.
+type DatasetQuery map[string]interface{}
+
+// NewDatasetQuery builds a query over the entity out of the filters. Keys of the filters can be dotted paths, which
+// are nested into the query, e.g. 'hasCase.hasGender=Female'.
+func NewDatasetQuery(entity string, filters map[string]string) DatasetQuery {
+	query := DatasetQuery{"entity": entity}
+	for key, value := range filters {
+		parts := strings.Split(key, ".")
+		node := map[string]interface{}(query)
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+	return query
+}
+
+// Entity returns the entity the query is over.
+func (q DatasetQuery) Entity() string {
+	entity, _ := q["entity"].(string)
+	return entity
+}
+
+// Datasets lists all the datasets available to the token holder.
+func (c Client) Datasets() ([]Dataset, error) {
+	u := mustParseURL(c.datasetsURL)
+	u.Path += "datasets"
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching datasets failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var r struct {
+		Embedded struct {
+			Datasets []Dataset `json:"datasets"`
+		} `json:"_embedded"`
+	}
+	if err := json.NewDecoder(resp).Decode(&r); err != nil {
+		return nil, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	sort.Slice(r.Embedded.Datasets, func(i, j int) bool { return r.Embedded.Datasets[i].ID < r.Embedded.Datasets[j].ID })
+
+	return r.Embedded.Datasets, nil
+}
+
+// DatasetSchema gets the schema of the entity of the dataset with datasetID, describing the properties the entity
+// can be filtered by.
+func (c Client) DatasetSchema(datasetID, entity string) (map[string]interface{}, error) {
+	u := mustParseURL(c.datasetsURL)
+	u.Path += fmt.Sprintf("datasets/%s/v1/%s/schema", datasetID, entity)
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching dataset schema failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var schema map[string]interface{}
+	if err := json.NewDecoder(resp).Decode(&schema); err != nil {
+		return nil, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return schema, nil
+}
+
+// QueryDataset lists all the entities of the dataset with datasetID matching the query, going through as many pages
+// as needed.
+func (c Client) QueryDataset(datasetID string, query DatasetQuery) ([]DatasetEntity, error) {
+	entities, err := collect(c.IterateDataset(datasetID, query, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("querying dataset failed: %s", err.Error())
+	}
+	return entities, nil
+}
+
+// IterateDataset returns an iterator over the entities of the dataset with datasetID matching the query. The query
+// is posted with every page, and the pages are followed by their offset, since the Datasets API doesn't link them.
+func (c Client) IterateDataset(datasetID string, query DatasetQuery, opts ListOptions) *Iterator[DatasetEntity] {
+	u := mustParseURL(c.datasetsURL)
+	u.Path += fmt.Sprintf("datasets/%s/v1/query", datasetID)
+	it := newIterator[DatasetEntity](c, u, opts)
+	it.method = http.MethodPost
+
+	entity := query.Entity()
+	if entity == "" {
+		it.err = fmt.Errorf("query has no entity")
+		return it
+	}
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		it.err = fmt.Errorf("encoding query failed: %s", err.Error())
+		return it
+	}
+	it.body = encoded
+
+	it.decode = func(r io.Reader) ([]DatasetEntity, string, error) {
+		var page struct {
+			Embedded map[string][]DatasetEntity `json:"_embedded"`
+		}
+		if err := json.NewDecoder(r).Decode(&page); err != nil {
+			return nil, "", err
+		}
+		// the iterator still points to the page being decoded
+		return page.Embedded[entity], nextOffset(it.next, len(page.Embedded[entity])), nil
+	}
+	return it
+}
+
+// CountDataset returns the number of entities of the dataset with datasetID matching the query.
+func (c Client) CountDataset(datasetID string, query DatasetQuery) (int, error) {
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		return 0, fmt.Errorf("encoding query failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.datasetsURL)
+	u.Path += fmt.Sprintf("datasets/%s/v1/query/total", datasetID)
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return 0, fmt.Errorf("counting dataset entities failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var r struct {
+		Total int `json:"total"`
+	}
+	if err := json.NewDecoder(resp).Decode(&r); err != nil {
+		return 0, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return r.Total, nil
+}
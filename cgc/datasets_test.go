@@ -0,0 +1,95 @@
+package cgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// handleDatasets serves a dataset with 250 BAM files, queried by the data format.
+func handleDatasets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/datasets/tcga/v1/query" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var query DatasetQuery
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	total := 0
+	if query["hasDataFormat"] == "BAM" {
+		total = 250
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	files := make([]DatasetEntity, 0)
+	for i := offset; i < total && i < offset+limit; i++ {
+		files = append(files, DatasetEntity{ID: fmt.Sprintf("file-%d", i)})
+	}
+
+	resp := map[string]interface{}{"_embedded": map[string]interface{}{"files": files}}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestNewDatasetQuery(t *testing.T) {
+	query := NewDatasetQuery(DatasetFiles, map[string]string{
+		"hasDataFormat":           "BAM",
+		"hasCase.hasGender":       "Female",
+		"hasCase.hasDiseaseType":  "Breast Invasive Carcinoma",
+		"hasSample.hasSampleType": "Primary Tumor",
+	})
+	expected := DatasetQuery{
+		"entity":        "files",
+		"hasDataFormat": "BAM",
+		"hasCase": map[string]interface{}{
+			"hasGender":      "Female",
+			"hasDiseaseType": "Breast Invasive Carcinoma",
+		},
+		"hasSample": map[string]interface{}{"hasSampleType": "Primary Tumor"},
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Fatalf("expected %v, got %v", expected, query)
+	}
+}
+
+func TestQueryDataset(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleDatasets)))
+	defer ts.Close()
+	client := New(testToken)
+	client.datasetsURL = ts.URL + "/"
+
+	files, err := client.QueryDataset("tcga", NewDatasetQuery(DatasetFiles, map[string]string{"hasDataFormat": "BAM"}))
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if len(files) != 250 || files[249].ID != "file-249" {
+		t.Fatalf("expected 250 files, got %d", len(files))
+	}
+
+	if _, err := client.QueryDataset("tcga", DatasetQuery{}); err == nil {
+		t.Fatalf("expected query without an entity to fail")
+	}
+
+	query := NewDatasetQuery(DatasetFiles, map[string]string{"hasDataFormat": "BAM"})
+	it := client.IterateDataset("tcga", query, ListOptions{Offset: 90, Limit: 120})
+	ids := make([]string, 0)
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if len(ids) != 120 || ids[0] != "file-90" || ids[119] != "file-209" {
+		t.Fatalf("expected files 90 to 209, got %d files", len(ids))
+	}
+}
@@ -0,0 +1,72 @@
+package cgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Instance struct represents the cloud instance a job was executed on.
+type Instance struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Provider string `json:"provider"`
+}
+
+// Job struct represents a single job of a task execution returned from CGC API. Logs maps the names of the log
+// files (e.g. 'job.err.log') to their file IDs.
+type Job struct {
+	Name        string            `json:"name"`
+	Status      string            `json:"status"`
+	CommandLine string            `json:"command_line"`
+	StartTime   time.Time         `json:"start_time"`
+	EndTime     time.Time         `json:"end_time"`
+	Instance    Instance          `json:"instance"`
+	Logs        map[string]string `json:"logs"`
+}
+
+// ExecutionDetails struct represents the details of a task execution returned from CGC API.
+type ExecutionDetails struct {
+	Href      string    `json:"href"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Jobs      []Job     `json:"jobs"`
+}
+
+// TaskExecutionDetails gets the execution details of the task that has the ID of taskID, including the status,
+// the instance and the log files of every job.
+func (c Client) TaskExecutionDetails(taskID string) (ExecutionDetails, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("tasks/%s/execution_details", taskID)
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return ExecutionDetails{}, fmt.Errorf("fetching task execution details failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var details ExecutionDetails
+	if err := json.NewDecoder(resp).Decode(&details); err != nil {
+		return ExecutionDetails{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	for i, job := range details.Jobs {
+		for name, log := range job.Logs {
+			details.Jobs[i].Logs[name] = logFileID(log)
+		}
+	}
+
+	return details, nil
+}
+
+// logFileID returns the file ID of a job log. The API references logs either by the file ID or by a link to the
+// file, in which case the ID is the path segment after 'files/'.
+func logFileID(log string) string {
+	i := strings.Index(log, "files/")
+	if i < 0 {
+		return log
+	}
+	return strings.SplitN(log[i+len("files/"):], "/", 2)[0]
+}
@@ -0,0 +1,53 @@
+package cgc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTaskExecutionDetails(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/tasks/"+testTaskID+"/execution_details" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			details := ExecutionDetails{
+				Status: TaskFailed,
+				Jobs: []Job{{
+					Name:   "align",
+					Status: TaskFailed,
+					Logs: map[string]string{
+						"cmd.log":     "log1",
+						"job.err.log": "https://cgc-api.sbgenomics.com/v2/files/log2/download_info",
+					},
+				}},
+			}
+			if err := json.NewEncoder(w).Encode(&details); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	details, err := client.TaskExecutionDetails(testTaskID)
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if len(details.Jobs) != 1 || details.Jobs[0].Name != "align" {
+		t.Fatalf("expected a single 'align' job, got %+v", details.Jobs)
+	}
+	logs := details.Jobs[0].Logs
+	if logs["cmd.log"] != "log1" || logs["job.err.log"] != "log2" {
+		t.Fatalf("expected log file IDs 'log1' and 'log2', got %+v", logs)
+	}
+
+	if _, err := client.TaskExecutionDetails("wrong"); err == nil {
+		t.Fatalf("expected wrong task ID to fail")
+	}
+}
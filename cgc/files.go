@@ -14,21 +14,32 @@ import (
 )
 
 type fileStorage struct {
-	Type     string `json:"type"`
-	Volume   string `json:"volume"`
-	Location string `json:"location"`
+	Type          string `json:"type"`
+	Volume        string `json:"volume"`
+	Location      string `json:"location"`
+	Class         string `json:"storage_class"`
+	RestoreStatus string `json:"restore_status"`
 }
 
 type fileOrigin struct {
 	Dataset string `json:"dataset"`
 }
 
-// File struct represents the file information returned from CGC API.
+// Types of entries in a project.
+const (
+	FileTypeFile   = "file"
+	FileTypeFolder = "folder"
+)
+
+// File struct represents the file information returned from CGC API. Folders are represented the same way, only
+// with Type set to FileTypeFolder.
 type File struct {
 	Project    string                 `json:"project"`
 	Href       string                 `json:"href"`
 	Name       string                 `json:"name"`
 	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Parent     string                 `json:"parent"`
 	Size       int64                  `json:"size"`
 	CreatedOn  time.Time              `json:"created_on"`
 	ModifiedOn time.Time              `json:"modified_on"`
@@ -38,53 +49,94 @@ type File struct {
 	Metadata   map[string]interface{} `json:"metadata"`
 }
 
-// Files lists all the files under the project with projectID.
-func (c Client) Files(projectID string) ([]File, error) {
-	u := mustParseURL(c.baseURL)
-	u.Path += "files"
-	params := url.Values{}
-	params.Add("project", projectID)
-	u.RawQuery = params.Encode()
-
-	files := make([]File, 0)
+// FileFilter narrows down the files listed under a project. Empty fields don't filter anything. A file matches
+// the filter if it has the exact Name, at least one of the Tags and all of the Metadata key-value pairs.
+type FileFilter struct {
+	Name     string
+	Tags     []string
+	Metadata map[string]string
+}
 
-	// if there's more files than returned by default by the API, links array will
-	// be provided. The object that has the 'rel' field with the value of 'next' will
-	// also contain the 'href' with the complete link to the next page.
-	for u != nil {
-		resp, err := c.request(http.MethodGet, u, nil)
-		if err != nil {
-			return nil, fmt.Errorf("fetching files failed: %s", err.Error())
+// Match reports whether the file matches the filter. The API applies the same rules when the filter is passed to
+// FilterFiles, this is used when the files are already at hand.
+func (f FileFilter) Match(file File) bool {
+	if f.Name != "" && f.Name != file.Name {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		found := false
+		for _, tag := range f.Tags {
+			if contains(file.Tags, tag) {
+				found = true
+				break
+			}
 		}
-		defer resp.Close()
-
-		var r struct {
-			apiOKResponseTemplate
-			Files []File `json:"items"`
+		if !found {
+			return false
 		}
-		if err := json.NewDecoder(resp).Decode(&r); err != nil {
-			return nil, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	for key, val := range f.Metadata {
+		v, ok := file.Metadata[key]
+		if !ok || v == nil || fmt.Sprint(v) != val {
+			return false
 		}
-		files = append(files, r.Files...)
+	}
+	return true
+}
 
-		u = nil
-		for _, link := range r.Links {
-			if link.Rel == "next" {
-				u = mustParseURL(link.Href)
-			}
-		}
+// values encodes the filter as query parameters understood by the 'files' endpoint.
+func (f FileFilter) values() url.Values {
+	params := url.Values{}
+	if f.Name != "" {
+		params.Add("name", f.Name)
 	}
+	for _, tag := range f.Tags {
+		params.Add("tag", tag)
+	}
+	for key, val := range f.Metadata {
+		params.Add("metadata."+key, val)
+	}
+	return params
+}
+
+// Files lists all the files under the project with projectID.
+func (c Client) Files(projectID string) ([]File, error) {
+	return c.FilterFiles(projectID, FileFilter{})
+}
 
+// FilterFiles lists the files under the project with projectID that match the filter. Filtering is done by the API.
+func (c Client) FilterFiles(projectID string, filter FileFilter) ([]File, error) {
+	files, err := collect(c.IterateFiles(projectID, filter, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching files failed: %s", err.Error())
+	}
 	return files, nil
 }
 
+// IterateFiles returns an iterator over the files under the project with projectID that match the filter.
+func (c Client) IterateFiles(projectID string, filter FileFilter, opts ListOptions) *Iterator[File] {
+	u := mustParseURL(c.baseURL)
+	u.Path += "files"
+	params := filter.values()
+	params.Add("project", projectID)
+	u.RawQuery = params.Encode()
+	return newIterator[File](c, u, opts)
+}
+
+// IterateFolder returns an iterator over the files and folders directly under the folder with folderID.
+func (c Client) IterateFolder(folderID string, opts ListOptions) *Iterator[File] {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("files/%s/list", folderID)
+	return newIterator[File](c, u, opts)
+}
+
 // StatFile gets the details of the file that has the ID of fileID.
 func (c Client) StatFile(fileID string) (File, error) {
 	u := mustParseURL(c.baseURL)
 	u.Path += fmt.Sprintf("files/%s", fileID)
 	resp, err := c.request(http.MethodGet, u, nil)
 	if err != nil {
-		return File{}, fmt.Errorf("fetching file details failed: %s", err.Error())
+		return File{}, fmt.Errorf("fetching file details failed: %w", err)
 	}
 	defer resp.Close()
 
@@ -99,8 +151,17 @@ func (c Client) StatFile(fileID string) (File, error) {
 // UpdateFile updates the file that has the ID of fileID. Updates slice represent strings with the format like
 // 'key=value' or 'metadata.key=value'. Every update string gets parsed, and a request to an appropriate endpoint is made.
 // If update string is used to update metadata, a PATCH request should be sent to 'files/{fileID}/metadata', else
-// 'files/{fileID}'.
+// 'files/{fileID}'. Metadata updates are validated against the standard metadata vocabulary before anything is sent,
+// and values that are invalid for a standard field are rejected.
 func (c Client) UpdateFile(fileID string, updates []string) error {
+	problems, err := ValidateUpdates(updates)
+	if err != nil {
+		return err
+	}
+	if err := MetadataError(problems, false); err != nil {
+		return err
+	}
+
 	for _, update := range updates {
 		encoded, isMetadata, err := updateStringToJSON(update)
 		if err != nil {
@@ -121,27 +182,62 @@ func (c Client) UpdateFile(fileID string, updates []string) error {
 	return nil
 }
 
-// DownloadFile downloads a file that has the ID of fileID and writes it to dest location on the system. Two requests have
-// to be made in order to make this happen. First one get's the download URL, and the second one actually downloads the file.
-func (c Client) DownloadFile(fileID, dest string) error {
+// UpdateFileTags replaces the tags of the file that has the ID of fileID with the given tags.
+func (c Client) UpdateFileTags(fileID string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	encoded, err := json.Marshal(map[string][]string{"tags": tags})
+	if err != nil {
+		return fmt.Errorf("encoding tags failed: %s", err.Error())
+	}
+
 	u := mustParseURL(c.baseURL)
-	u.Path += fmt.Sprintf("files/%s/download_info", fileID)
-	resp, err := c.request(http.MethodGet, u, nil)
+	u.Path += fmt.Sprintf("files/%s/", fileID)
+	resp, err := c.request(http.MethodPatch, u, bytes.NewReader(encoded))
 	if err != nil {
-		return fmt.Errorf("fetching file details failed: %s", err.Error())
+		return fmt.Errorf("updating file tags failed: %s", err.Error())
 	}
 	defer resp.Close()
 
-	var r struct {
-		URL string `json:"url"`
+	return nil
+}
+
+// DeleteFile deletes the file that has the ID of fileID.
+func (c Client) DeleteFile(fileID string) error {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("files/%s", fileID)
+	resp, err := c.request(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("deleting file failed: %s", err.Error())
 	}
-	if err := json.NewDecoder(resp).Decode(&r); err != nil {
-		return fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	defer resp.Close()
+
+	return nil
+}
+
+// ValidateUpdates parses the metadata update strings (the ones in the 'metadata.key=value' format) and validates
+// them against the standard metadata vocabulary. Other update strings are only checked for being well formed.
+func ValidateUpdates(updates []string) ([]MetadataProblem, error) {
+	metadata := make(map[string]interface{})
+	for _, update := range updates {
+		key, value, isMetadata, err := parseUpdateString(update)
+		if err != nil {
+			return nil, fmt.Errorf("parsing update string '%s' failed: %s", update, err.Error())
+		}
+		if isMetadata {
+			metadata[key] = value
+		}
 	}
 
-	file, err := c.request(http.MethodGet, mustParseURL(r.URL), nil)
+	return ValidateMetadata(metadata), nil
+}
+
+// DownloadFile downloads a file that has the ID of fileID and writes it to dest location on the system.
+func (c Client) DownloadFile(fileID, dest string) error {
+	file, err := c.OpenFile(fileID)
 	if err != nil {
-		return fmt.Errorf("download link failed: %s", err.Error())
+		return err
 	}
 	defer file.Close()
 
@@ -149,6 +245,7 @@ func (c Client) DownloadFile(fileID, dest string) error {
 	if err != nil {
 		return fmt.Errorf("creating '%s' file failed: %s", dest, err.Error())
 	}
+	defer destf.Close()
 
 	if _, err := io.Copy(destf, file); err != nil {
 		return fmt.Errorf("writing file to '%s' failed: %s", dest, err.Error())
@@ -161,12 +258,59 @@ func (c Client) DownloadFile(fileID, dest string) error {
 	return nil
 }
 
+// OpenFile opens a file that has the ID of fileID for reading its contents. Two requests have to be made in order
+// to make this happen. First one get's the download URL, and the second one actually downloads the file. The caller
+// is responsible for closing the returned reader.
+func (c Client) OpenFile(fileID string) (io.ReadCloser, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("files/%s/download_info", fileID)
+	// download links expire, so they can't come from the cache
+	resp, err := c.WithCache(nil).request(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching file details failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var r struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp).Decode(&r); err != nil {
+		return nil, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	file, err := c.request(http.MethodGet, mustParseURL(r.URL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("download link failed: %s", err.Error())
+	}
+
+	return file, nil
+}
+
 // updateStringToJSON parses a string in a format of 'key=value', 'metadata.key=value' and encodes it in JSON format.
 // Returns true if the string is prefixed with 'metadata.'.
 func updateStringToJSON(updateString string) ([]byte, bool, error) {
+	key, value, isMetadata, err := parseUpdateString(updateString)
+	if err != nil {
+		return nil, false, err
+	}
+
+	toEncode := map[string]interface{}{
+		key: value,
+	}
+	buff := bytes.Buffer{}
+	if err := json.NewEncoder(&buff).Encode(toEncode); err != nil {
+		return nil, false, fmt.Errorf("encoding failed: %s", err)
+	}
+
+	return buff.Bytes(), isMetadata, nil
+}
+
+// parseUpdateString parses a string in a format of 'key=value', 'metadata.key=value' into a key and a value of the
+// type the value should have when encoded in JSON. Returns true if the string is prefixed with 'metadata.'.
+func parseUpdateString(updateString string) (string, interface{}, bool, error) {
 	kv := strings.Split(updateString, "=")
 	if len(kv) != 2 {
-		return nil, false, fmt.Errorf("malformed update string")
+		return "", nil, false, fmt.Errorf("malformed update string")
 	}
 	key := kv[0]
 	val := kv[1]
@@ -199,13 +343,5 @@ func updateStringToJSON(updateString string) ([]byte, bool, error) {
 		value = val
 	}
 
-	toEncode := map[string]interface{}{
-		key: value,
-	}
-	buff := bytes.Buffer{}
-	if err := json.NewEncoder(&buff).Encode(toEncode); err != nil {
-		return nil, false, fmt.Errorf("encoding failed: %s", err)
-	}
-
-	return buff.Bytes(), isMetadata, nil
+	return key, value, isMetadata, nil
 }
@@ -190,6 +190,55 @@ func TestStatFile(t *testing.T) {
 	}
 }
 
+func TestDeleteFile(t *testing.T) {
+	type in struct {
+		fileID string
+	}
+	type out struct {
+		err error
+	}
+	td := []struct {
+		label string
+		in    in
+		out   out
+	}{
+		{"All good", in{testFileID}, out{nil}},
+		{"Wrong File ID", in{"wrong"}, out{errors.New("not found")}},
+	}
+
+	testToken := "test_token"
+	contentType := "application/json"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		handleStatFile(w, r)
+	}
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handler)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			err := client.DeleteFile(tt.in.fileID)
+			if err != nil {
+				if tt.out.err != nil {
+					if !strings.Contains(err.Error(), tt.out.err.Error()) {
+						t.Fatalf("expected '%v', got '%v'", tt.out.err, err)
+					}
+					return
+				}
+				t.Fatalf("expected no error, got '%v'", err)
+			}
+			if tt.out.err != nil {
+				t.Fatalf("expected error '%v', got none", tt.out.err)
+			}
+		})
+	}
+}
+
 func TestUpdateStringToJSON(t *testing.T) {
 	type in struct {
 		updateString string
@@ -293,3 +342,28 @@ func TestUpdateStringToJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestFileFilterMatch(t *testing.T) {
+	td := []struct {
+		label  string
+		filter FileFilter
+		match  bool
+	}{
+		{"Empty", FileFilter{}, true},
+		{"Name", FileFilter{Name: "baz"}, true},
+		{"Wrong name", FileFilter{Name: "qux"}, false},
+		{"Any tag", FileFilter{Tags: []string{"qux", "bar"}}, true},
+		{"Missing tag", FileFilter{Tags: []string{"qux"}}, false},
+		{"Metadata", FileFilter{Metadata: map[string]string{"foo": "42", "bar": "baz"}}, true},
+		{"Wrong metadata", FileFilter{Metadata: map[string]string{"foo": "43"}}, false},
+		{"Missing metadata", FileFilter{Metadata: map[string]string{"qux": "42"}}, false},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			if match := tt.filter.Match(sampleFile()); match != tt.match {
+				t.Fatalf("expected match to be %t, got %t", tt.match, match)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package cgc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Member struct represents a member of a project returned from CGC API.
+type Member struct {
+	Href        string      `json:"href"`
+	ID          string      `json:"id"`
+	Username    string      `json:"username"`
+	Email       string      `json:"email"`
+	Type        string      `json:"type"`
+	Permissions Permissions `json:"permissions"`
+}
+
+// RosterDiff describes how the members of a project differ from a roster. Add holds the roster members missing
+// from the project, Update the roster members whose permissions in the project differ, and Remove the project
+// members that are not on the roster.
+type RosterDiff struct {
+	Add    []Member
+	Update []Member
+	Remove []Member
+}
+
+// Empty reports whether the project members match the roster.
+func (d RosterDiff) Empty() bool {
+	return len(d.Add) == 0 && len(d.Update) == 0 && len(d.Remove) == 0
+}
+
+// String returns the permissions that are granted, separated by commas (e.g. 'read,copy,write').
+func (p Permissions) String() string {
+	names := make([]string, 0)
+	for _, perm := range []struct {
+		name    string
+		granted bool
+	}{
+		{"read", p.Read},
+		{"copy", p.Copy},
+		{"write", p.Write},
+		{"execute", p.Execute},
+		{"admin", p.Admin},
+	} {
+		if perm.granted {
+			names = append(names, perm.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParsePermissions parses permissions from a string of permission names separated by commas, the same format
+// Permissions.String returns.
+func ParsePermissions(s string) (Permissions, error) {
+	var p Permissions
+	for _, name := range strings.Split(s, ",") {
+		switch strings.TrimSpace(name) {
+		case "read":
+			p.Read = true
+		case "copy":
+			p.Copy = true
+		case "write":
+			p.Write = true
+		case "execute":
+			p.Execute = true
+		case "admin":
+			p.Admin = true
+		case "":
+		default:
+			return Permissions{}, fmt.Errorf("unknown permission '%s'", name)
+		}
+	}
+	return p, nil
+}
+
+// ProjectMembers lists all the members of the project with projectID.
+func (c Client) ProjectMembers(projectID string) ([]Member, error) {
+	members, err := collect(c.IterateProjectMembers(projectID, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching project members failed: %s", err.Error())
+	}
+	return members, nil
+}
+
+// IterateProjectMembers returns an iterator over the members of the project with projectID.
+func (c Client) IterateProjectMembers(projectID string, opts ListOptions) *Iterator[Member] {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("projects/%s/members", projectID)
+	return newIterator[Member](c, u, opts)
+}
+
+// AddMember adds the user with the given username to the project with projectID, granting them the permissions.
+func (c Client) AddMember(projectID, username string, permissions Permissions) (Member, error) {
+	body := struct {
+		Username    string      `json:"username"`
+		Permissions Permissions `json:"permissions"`
+	}{username, permissions}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return Member{}, fmt.Errorf("encoding member failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("projects/%s/members", projectID)
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return Member{}, fmt.Errorf("adding member failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var member Member
+	if err := json.NewDecoder(resp).Decode(&member); err != nil {
+		return Member{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return member, nil
+}
+
+// UpdateMemberPermissions replaces the permissions the member with the given username has in the project with
+// projectID. Returns the permissions as they are after the update.
+func (c Client) UpdateMemberPermissions(projectID, username string, permissions Permissions) (Permissions, error) {
+	encoded, err := json.Marshal(permissions)
+	if err != nil {
+		return Permissions{}, fmt.Errorf("encoding permissions failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	addEscapedPath(u, fmt.Sprintf("projects/%s/members/%s/permissions", projectID, url.PathEscape(username)))
+	resp, err := c.request(http.MethodPatch, u, bytes.NewReader(encoded))
+	if err != nil {
+		return Permissions{}, fmt.Errorf("updating member permissions failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var updated Permissions
+	if err := json.NewDecoder(resp).Decode(&updated); err != nil {
+		return Permissions{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return updated, nil
+}
+
+// RemoveMember removes the member with the given username from the project with projectID.
+func (c Client) RemoveMember(projectID, username string) error {
+	u := mustParseURL(c.baseURL)
+	addEscapedPath(u, fmt.Sprintf("projects/%s/members/%s", projectID, url.PathEscape(username)))
+	resp, err := c.request(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("removing member failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	return nil
+}
+
+// DiffRoster compares the current members of a project with the roster, the list of members the project should
+// have. Members are matched by username.
+func DiffRoster(current, roster []Member) RosterDiff {
+	var diff RosterDiff
+	byUsername := make(map[string]Member)
+	for _, m := range current {
+		byUsername[m.Username] = m
+	}
+
+	for _, want := range roster {
+		have, ok := byUsername[want.Username]
+		if !ok {
+			diff.Add = append(diff.Add, want)
+		} else if have.Permissions != want.Permissions {
+			diff.Update = append(diff.Update, want)
+		}
+		delete(byUsername, want.Username)
+	}
+	for _, m := range current {
+		if _, ok := byUsername[m.Username]; ok {
+			diff.Remove = append(diff.Remove, m)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,74 @@
+package cgc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParsePermissions(t *testing.T) {
+	p, err := ParsePermissions("read, write,admin")
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if p != (Permissions{Read: true, Write: true, Admin: true}) {
+		t.Fatalf("unexpected permissions: %+v", p)
+	}
+	if p.String() != "read,write,admin" {
+		t.Fatalf("expected 'read,write,admin', got '%s'", p.String())
+	}
+
+	if _, err := ParsePermissions("read,own"); err == nil {
+		t.Fatalf("expected unknown permission to fail")
+	}
+}
+
+func TestDiffRoster(t *testing.T) {
+	read := Permissions{Read: true}
+	write := Permissions{Read: true, Write: true}
+	current := []Member{
+		{Username: "alice", Permissions: read},
+		{Username: "bob", Permissions: read},
+		{Username: "carol", Permissions: write},
+	}
+	roster := []Member{
+		{Username: "alice", Permissions: read},
+		{Username: "bob", Permissions: write},
+		{Username: "dave", Permissions: read},
+	}
+
+	diff := DiffRoster(current, roster)
+	if len(diff.Add) != 1 || diff.Add[0].Username != "dave" {
+		t.Fatalf("expected 'dave' to be added, got %+v", diff.Add)
+	}
+	if len(diff.Update) != 1 || diff.Update[0].Username != "bob" || diff.Update[0].Permissions != write {
+		t.Fatalf("expected 'bob' to be updated, got %+v", diff.Update)
+	}
+	if len(diff.Remove) != 1 || diff.Remove[0].Username != "carol" {
+		t.Fatalf("expected 'carol' to be removed, got %+v", diff.Remove)
+	}
+
+	if !DiffRoster(roster, roster).Empty() {
+		t.Fatalf("expected no difference between the same rosters")
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	var requested string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.EscapedPath()
+	}
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handler)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	if err := client.RemoveMember("owner/project", "division/alice"); err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if want := "/projects/owner/project/members/division%2Falice"; requested != want {
+		t.Fatalf("expected '%s' to be requested, got '%s'", want, requested)
+	}
+}
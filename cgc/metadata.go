@@ -0,0 +1,189 @@
+package cgc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MetadataType describes the kind of value a standard metadata field holds.
+type MetadataType int
+
+// Kinds of values a standard metadata field can hold.
+const (
+	MetadataString MetadataType = iota
+	MetadataInteger
+	MetadataEnum
+)
+
+// MetadataField describes a single field from the CGC standard metadata vocabulary. Values is only set for
+// fields of MetadataEnum type and contains all the values that the API accepts for that field.
+type MetadataField struct {
+	Name   string
+	Type   MetadataType
+	Values []string
+}
+
+// MetadataProblem describes a single issue found while validating metadata. Unknown problems are the ones
+// caused by keys that are not a part of the standard vocabulary, they are only warnings unless validation
+// is strict.
+type MetadataProblem struct {
+	Key     string
+	Message string
+	Unknown bool
+}
+
+func (p MetadataProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Key, p.Message)
+}
+
+// StandardMetadata is the CGC standard metadata vocabulary, keyed by the field name.
+var StandardMetadata = map[string]MetadataField{
+	// file
+	"experimental_strategy": {
+		Name: "experimental_strategy",
+		Type: MetadataEnum,
+		Values: []string{
+			"WGS", "WXS", "WGA", "RNA-Seq", "miRNA-Seq", "Bisulfite-Seq", "Validation", "Amplicon",
+			"Targeted Sequencing", "Genotyping Array", "Methylation Array", "Total RNA-Seq", "Other",
+		},
+	},
+	"library_id": {Name: "library_id", Type: MetadataString},
+	"platform": {
+		Name: "platform",
+		Type: MetadataEnum,
+		Values: []string{
+			"Illumina", "SOLiD", "LS454", "Helicos", "Ion Torrent", "PacBio", "Complete Genomics",
+			"Capillary", "Oxford Nanopore", "Affymetrix SNP Array 6.0", "Illumina Human Methylation 450",
+			"Illumina Human Methylation 27", "Other",
+		},
+	},
+	"platform_unit_id":    {Name: "platform_unit_id", Type: MetadataString},
+	"file_segment_number": {Name: "file_segment_number", Type: MetadataInteger},
+	"quality_scale": {
+		Name:   "quality_scale",
+		Type:   MetadataEnum,
+		Values: []string{"sanger", "illumina13", "illumina15", "illumina18", "solexa"},
+	},
+	"paired_end": {
+		Name:   "paired_end",
+		Type:   MetadataEnum,
+		Values: []string{"1", "2"},
+	},
+	"reference_genome": {Name: "reference_genome", Type: MetadataString},
+	"data_format":      {Name: "data_format", Type: MetadataString},
+	"data_type":        {Name: "data_type", Type: MetadataString},
+	"data_subtype":     {Name: "data_subtype", Type: MetadataString},
+	"investigation":    {Name: "investigation", Type: MetadataString},
+
+	// case
+	"case_id":   {Name: "case_id", Type: MetadataString},
+	"case_uuid": {Name: "case_uuid", Type: MetadataString},
+	"gender": {
+		Name:   "gender",
+		Type:   MetadataEnum,
+		Values: []string{"male", "female", "unknown"},
+	},
+	"race":             {Name: "race", Type: MetadataString},
+	"ethnicity":        {Name: "ethnicity", Type: MetadataString},
+	"primary_site":     {Name: "primary_site", Type: MetadataString},
+	"disease_type":     {Name: "disease_type", Type: MetadataString},
+	"age_at_diagnosis": {Name: "age_at_diagnosis", Type: MetadataInteger},
+	"vital_status": {
+		Name:   "vital_status",
+		Type:   MetadataEnum,
+		Values: []string{"alive", "dead", "unknown"},
+	},
+	"days_to_death": {Name: "days_to_death", Type: MetadataInteger},
+
+	// sample
+	"sample_id":   {Name: "sample_id", Type: MetadataString},
+	"sample_uuid": {Name: "sample_uuid", Type: MetadataString},
+	"sample_type": {
+		Name: "sample_type",
+		Type: MetadataEnum,
+		Values: []string{
+			"Primary Tumor", "Recurrent Tumor", "Primary Blood Derived Cancer - Peripheral Blood",
+			"Recurrent Blood Derived Cancer - Bone Marrow", "Additional - New Primary", "Metastatic",
+			"Additional Metastatic", "Human Tumor Original Cells", "Primary Blood Derived Cancer - Bone Marrow",
+			"Blood Derived Normal", "Solid Tissue Normal", "Buccal Cell Normal", "EBV Immortalized Normal",
+			"Bone Marrow Normal", "Control Analyte", "Cell Lines", "Primary Xenograft Tissue",
+			"Cell Line Derived Xenograft Tissue",
+		},
+	},
+	"species": {Name: "species", Type: MetadataString},
+
+	// aliquot
+	"aliquot_id":   {Name: "aliquot_id", Type: MetadataString},
+	"aliquot_uuid": {Name: "aliquot_uuid", Type: MetadataString},
+}
+
+// ValidateMetadata checks metadata against the CGC standard metadata vocabulary. Values that don't fit the
+// type of a standard field are always reported, while keys that are not a part of the vocabulary are reported
+// as Unknown problems. Null values are accepted for every field, since they are used to clear it. Problems are
+// sorted by key.
+func ValidateMetadata(metadata map[string]interface{}) []MetadataProblem {
+	problems := make([]MetadataProblem, 0)
+	for key, value := range metadata {
+		field, ok := StandardMetadata[key]
+		if !ok {
+			problems = append(problems, MetadataProblem{
+				Key:     key,
+				Message: "not a standard metadata field",
+				Unknown: true,
+			})
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if msg := field.check(value); msg != "" {
+			problems = append(problems, MetadataProblem{Key: key, Message: msg})
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Key < problems[j].Key })
+
+	return problems
+}
+
+// MetadataError returns an error describing all the problems that should stop metadata from being sent to the
+// API. Problems with unknown keys are only taken into account if strict is true. Returns nil if there are none.
+func MetadataError(problems []MetadataProblem, strict bool) error {
+	msgs := make([]string, 0)
+	for _, p := range problems {
+		if !p.Unknown || strict {
+			msgs = append(msgs, p.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid metadata: %s", strings.Join(msgs, "; "))
+}
+
+// check returns a message describing why value is not valid for the field, or an empty string if it is.
+func (f MetadataField) check(value interface{}) string {
+	switch f.Type {
+	case MetadataInteger:
+		switch n := value.(type) {
+		case int, int64:
+		case float64:
+			if n != math.Trunc(n) {
+				return fmt.Sprintf("expected an integer, got '%v'", value)
+			}
+		default:
+			return fmt.Sprintf("expected an integer, got '%v'", value)
+		}
+	case MetadataEnum:
+		s := fmt.Sprint(value)
+		for _, v := range f.Values {
+			if s == v {
+				return ""
+			}
+		}
+		return fmt.Sprintf("'%s' is not one of: %s", s, strings.Join(f.Values, ", "))
+	}
+	return ""
+}
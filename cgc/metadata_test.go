@@ -0,0 +1,97 @@
+package cgc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	type in struct {
+		metadata map[string]interface{}
+		strict   bool
+	}
+	type out struct {
+		problems int
+		err      error
+	}
+
+	td := []struct {
+		label string
+		in    in
+		out   out
+	}{
+		{
+			"All good",
+			in{map[string]interface{}{"platform": "Illumina", "paired_end": float64(1), "sample_id": "s1"}, false},
+			out{0, nil},
+		},
+		{
+			"Null value",
+			in{map[string]interface{}{"sample_type": nil}, true},
+			out{0, nil},
+		},
+		{
+			"Unknown key",
+			in{map[string]interface{}{"foo": "bar"}, false},
+			out{1, nil},
+		},
+		{
+			"Unknown key strict",
+			in{map[string]interface{}{"foo": "bar"}, true},
+			out{1, errors.New("not a standard metadata field")},
+		},
+		{
+			"Invalid enum",
+			in{map[string]interface{}{"paired_end": float64(3)}, false},
+			out{1, errors.New("'3' is not one of")},
+		},
+		{
+			"Invalid integer",
+			in{map[string]interface{}{"age_at_diagnosis": "old"}, false},
+			out{1, errors.New("expected an integer")},
+		},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			problems := ValidateMetadata(tt.in.metadata)
+			if len(problems) != tt.out.problems {
+				t.Fatalf("expected %d problems, got %d", tt.out.problems, len(problems))
+			}
+			err := MetadataError(problems, tt.in.strict)
+			if err != nil {
+				if tt.out.err != nil {
+					if !strings.Contains(err.Error(), tt.out.err.Error()) {
+						t.Fatalf("expected '%v', got '%v'", tt.out.err, err)
+					}
+					return
+				}
+				t.Fatalf("expected no error, got '%v'", err)
+			}
+			if tt.out.err != nil {
+				t.Fatalf("expected '%v', got no error", tt.out.err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdates(t *testing.T) {
+	problems, err := ValidateUpdates([]string{"name=foo", "metadata.quality_scale=phred", "metadata.bar=baz"})
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d", len(problems))
+	}
+	if problems[0].Key != "bar" || !problems[0].Unknown {
+		t.Fatalf("expected unknown 'bar' key, got '%s'", problems[0])
+	}
+	if problems[1].Key != "quality_scale" || problems[1].Unknown {
+		t.Fatalf("expected invalid 'quality_scale' key, got '%s'", problems[1])
+	}
+
+	if _, err := ValidateUpdates([]string{"asdf"}); err == nil {
+		t.Fatalf("expected malformed update string to fail")
+	}
+}
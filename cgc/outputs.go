@@ -0,0 +1,113 @@
+package cgc
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// OutputFile is a single file produced by a task. Path is the path of the file relative to the output port, which
+// includes the names of the folders the file is nested in.
+type OutputFile struct {
+	Port   string
+	Path   string
+	FileID string
+}
+
+// TaskOutputFiles resolves every file referenced by the outputs of the task, including the files in arrays, the
+// secondary files and the files nested in output folders. If ports are given, only the outputs on those ports are
+// resolved. Outputs that are not files (e.g. strings or numbers) are ignored. Files are sorted by port and path.
+func (c Client) TaskOutputFiles(task Task, ports ...string) ([]OutputFile, error) {
+	files := make([]OutputFile, 0)
+	for port, value := range task.Outputs {
+		if len(ports) > 0 && !contains(ports, port) {
+			continue
+		}
+		resolved, err := c.resolveOutput(port, "", value)
+		if err != nil {
+			return nil, fmt.Errorf("resolving output '%s' failed: %s", port, err.Error())
+		}
+		files = append(files, resolved...)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Port != files[j].Port {
+			return files[i].Port < files[j].Port
+		}
+		return files[i].Path < files[j].Path
+	})
+
+	return files, nil
+}
+
+// resolveOutput resolves the files referenced by an output value, placing them under the dir path.
+func (c Client) resolveOutput(port, dir string, value interface{}) ([]OutputFile, error) {
+	files := make([]OutputFile, 0)
+
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			resolved, err := c.resolveOutput(port, dir, item)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, resolved...)
+		}
+	case map[string]interface{}:
+		id, _ := v["path"].(string)
+		if id == "" {
+			return files, nil
+		}
+		name, _ := v["name"].(string)
+		if name == "" {
+			file, err := c.StatFile(id)
+			if err != nil {
+				return nil, err
+			}
+			name = file.Name
+		}
+
+		switch v["class"] {
+		case "File":
+			files = append(files, OutputFile{Port: port, Path: path.Join(dir, name), FileID: id})
+			if secondary, ok := v["secondaryFiles"]; ok {
+				resolved, err := c.resolveOutput(port, dir, secondary)
+				if err != nil {
+					return nil, err
+				}
+				files = append(files, resolved...)
+			}
+		case "Directory":
+			resolved, err := c.folderFiles(port, path.Join(dir, name), id)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, resolved...)
+		}
+	}
+
+	return files, nil
+}
+
+// folderFiles lists all the files under the folder with folderID, going through the nested folders as well.
+func (c Client) folderFiles(port, dir, folderID string) ([]OutputFile, error) {
+	entries, err := collect(c.IterateFolder(folderID, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("listing folder '%s' failed: %s", folderID, err.Error())
+	}
+
+	files := make([]OutputFile, 0)
+	for _, entry := range entries {
+		if entry.Type == FileTypeFolder {
+			nested, err := c.folderFiles(port, path.Join(dir, entry.Name), entry.ID)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, nested...)
+			continue
+		}
+		files = append(files, OutputFile{Port: port, Path: path.Join(dir, entry.Name), FileID: entry.ID})
+	}
+
+	return files, nil
+}
@@ -0,0 +1,87 @@
+package cgc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func handleFolders(w http.ResponseWriter, r *http.Request) {
+	var resp struct {
+		apiOKResponseTemplate
+		Items []File `json:"items"`
+	}
+	switch {
+	case strings.HasSuffix(r.URL.Path, "files/folder/list"):
+		resp.Items = []File{
+			{ID: "f3", Name: "c.txt", Type: FileTypeFile},
+			{ID: "nested", Name: "nested", Type: FileTypeFolder},
+		}
+	case strings.HasSuffix(r.URL.Path, "files/nested/list"):
+		resp.Items = []File{{ID: "f4", Name: "d.txt", Type: FileTypeFile}}
+	case strings.HasSuffix(r.URL.Path, "files/f2"):
+		file := File{ID: "f2", Name: "b.bam.bai", Type: FileTypeFile}
+		if err := json.NewEncoder(w).Encode(&file); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestTaskOutputFiles(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleFolders)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	var task Task
+	outputs := `{
+		"aligned": [[{
+			"class": "File", "path": "f1", "name": "b.bam",
+			"secondaryFiles": [{"class": "File", "path": "f2"}]
+		}]],
+		"reports": {"class": "Directory", "path": "folder", "name": "reports"},
+		"count": 42,
+		"missing": null
+	}`
+	if err := json.Unmarshal([]byte(`{"outputs":`+outputs+`}`), &task); err != nil {
+		t.Fatalf("unmarshalling task failed: %s", err.Error())
+	}
+
+	files, err := client.TaskOutputFiles(task)
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	expected := []OutputFile{
+		{"aligned", "b.bam", "f1"},
+		{"aligned", "b.bam.bai", "f2"},
+		{"reports", "reports/c.txt", "f3"},
+		{"reports", "reports/nested/d.txt", "f4"},
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("expected %d files, got %d: %+v", len(expected), len(files), files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Fatalf("expected '%+v', got '%+v'", expected[i], files[i])
+		}
+	}
+
+	files, err = client.TaskOutputFiles(task, "reports")
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files on 'reports' port, got %d", len(files))
+	}
+}
@@ -0,0 +1,202 @@
+package cgc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// maxPageSize is the largest number of items the API returns in a single page.
+	maxPageSize = 100
+	// totalHeader holds the number of items matching the query across all pages.
+	totalHeader = "X-Total-Matching-Query"
+)
+
+// ListOptions controls which part of a list gets iterated over. Offset is the number of items to skip from the
+// start of the list, and Limit is the maximum number of items to go through. Limit of zero means no limit.
+type ListOptions struct {
+	Offset int
+	Limit  int
+}
+
+// Iterator goes through the items of a list endpoint, fetching the pages one by one as they are needed. Only a
+// single page is kept in memory at a time, and no more pages are fetched once the caller stops calling Next.
+//
+//	it := client.IterateProjects(cgc.ListOptions{})
+//	for it.Next() {
+//		fmt.Println(it.Item().ID)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type Iterator[T any] struct {
+	client    Client
+	decode    pageDecoder[T]
+	method    string
+	body      []byte
+	next      *url.URL
+	remaining int
+	page      []T
+	item      T
+	total     int
+	hasTotal  bool
+	err       error
+}
+
+// pageDecoder decodes a single page of a list endpoint into its items and the link to the next page, which is
+// empty on the last page. Endpoints that don't return their items the usual way bring their own decoder.
+type pageDecoder[T any] func(r io.Reader) ([]T, string, error)
+
+// decodeItems decodes a page that holds its items under 'items'. If there's more items than returned in a single
+// page, links array will be provided. The object that has the 'rel' field with the value of 'next' will also
+// contain the 'href' with the complete link to the next page.
+func decodeItems[T any](r io.Reader) ([]T, string, error) {
+	var page struct {
+		apiOKResponseTemplate
+		Items []T `json:"items"`
+	}
+	if err := json.NewDecoder(r).Decode(&page); err != nil {
+		return nil, "", err
+	}
+	return page.Items, page.next(), nil
+}
+
+// next returns the link to the next page, or an empty string if there's none.
+func (r apiOKResponseTemplate) next() string {
+	for _, link := range r.Links {
+		if link.Rel == "next" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// newIterator returns an iterator over the list endpoint at u. Paging query parameters are added to u.
+func newIterator[T any](c Client, u *url.URL, opts ListOptions) *Iterator[T] {
+	params := u.Query()
+	pageSize := maxPageSize
+	if opts.Limit > 0 && opts.Limit < pageSize {
+		pageSize = opts.Limit
+	}
+	params.Set("limit", strconv.Itoa(pageSize))
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	u.RawQuery = params.Encode()
+
+	remaining := -1
+	if opts.Limit > 0 {
+		remaining = opts.Limit
+	}
+
+	return &Iterator[T]{
+		client:    c,
+		decode:    decodeItems[T],
+		method:    http.MethodGet,
+		next:      u,
+		remaining: remaining,
+	}
+}
+
+// Next advances the iterator to the next item, fetching the next page if the current one is exhausted. Returns
+// false when there are no more items or an error occurred, which is then available through Err.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || it.remaining == 0 {
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if it.next == nil {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.item, it.page = it.page[0], it.page[1:]
+	if it.remaining > 0 {
+		it.remaining--
+	}
+	return true
+}
+
+// Item returns the item the iterator is currently at.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Total returns the number of items matching the query, as reported by the API with the first page. The second
+// return value is false if the API didn't report it or no page was fetched yet.
+func (it *Iterator[T]) Total() (int, bool) {
+	return it.total, it.hasTotal
+}
+
+// fetch gets the next page and sets the link to the one after it. Endpoints that take the query in the body of a
+// POST request get the same body with every page.
+func (it *Iterator[T]) fetch() error {
+	var body io.Reader
+	if it.body != nil {
+		body = bytes.NewReader(it.body)
+	}
+	resp, err := it.client.do(it.method, it.next, body)
+	if err != nil {
+		return fmt.Errorf("fetching page failed: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	page, next, err := it.decode(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	it.page = page
+
+	if total, err := strconv.Atoi(resp.Header.Get(totalHeader)); err == nil && !it.hasTotal {
+		it.total, it.hasTotal = total, true
+	}
+
+	it.next = nil
+	if next != "" {
+		it.next = mustParseURL(next)
+	}
+
+	return nil
+}
+
+// nextOffset returns the link to the page after the one at u, for endpoints that are paged by the offset alone and
+// don't link to the next page. The page at u had n items, so there's no next page if it wasn't full.
+func nextOffset(u *url.URL, n int) string {
+	params := u.Query()
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	if n == 0 || n < limit {
+		return ""
+	}
+	offset, _ := strconv.Atoi(params.Get("offset"))
+	params.Set("offset", strconv.Itoa(offset+n))
+	next := *u
+	next.RawQuery = params.Encode()
+	return next.String()
+}
+
+// collect goes through all the items of the iterator and returns them in a slice.
+func collect[T any](it *Iterator[T]) ([]T, error) {
+	items := make([]T, 0)
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,116 @@
+package cgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// pagedServer serves count projects, honoring the offset and limit query parameters and linking to the next page
+// the same way the API does. Every request is counted in requests.
+func pagedServer(count int, requests *int) *httptest.Server {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var resp struct {
+			apiOKResponseTemplate
+			Items []Project `json:"items"`
+		}
+		resp.Items = make([]Project, 0)
+		for i := offset; i < offset+limit && i < count; i++ {
+			resp.Items = append(resp.Items, Project{ID: strconv.Itoa(i)})
+		}
+		if offset+limit < count {
+			resp.Links = append(resp.Links, struct {
+				Href   string `json:"href"`
+				Rel    string `json:"rel"`
+				Method string `json:"method"`
+			}{
+				Href: fmt.Sprintf("%s%s?offset=%d&limit=%d", ts.URL, r.URL.Path, offset+limit, limit),
+				Rel:  "next",
+			})
+		}
+
+		w.Header().Set(totalHeader, strconv.Itoa(count))
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	return ts
+}
+
+func TestIterator(t *testing.T) {
+	type in struct {
+		count int
+		opts  ListOptions
+	}
+	type out struct {
+		first    string
+		items    int
+		requests int
+	}
+
+	td := []struct {
+		label string
+		in    in
+		out   out
+	}{
+		{"Empty", in{0, ListOptions{}}, out{"", 0, 1}},
+		{"Single page", in{42, ListOptions{}}, out{"0", 42, 1}},
+		{"Many pages", in{250, ListOptions{}}, out{"0", 250, 3}},
+		{"Offset", in{250, ListOptions{Offset: 120}}, out{"120", 130, 2}},
+		{"Limit", in{250, ListOptions{Limit: 10}}, out{"0", 10, 1}},
+		{"Offset and limit", in{250, ListOptions{Offset: 5, Limit: 150}}, out{"5", 150, 2}},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			requests := 0
+			ts := pagedServer(tt.in.count, &requests)
+			defer ts.Close()
+			client := New("")
+			client.baseURL = ts.URL + "/"
+
+			projects, err := collect(client.IterateProjects(tt.in.opts))
+			if err != nil {
+				t.Fatalf("expected no error, got '%v'", err)
+			}
+			if len(projects) != tt.out.items {
+				t.Fatalf("expected %d items, got %d", tt.out.items, len(projects))
+			}
+			if len(projects) > 0 && projects[0].ID != tt.out.first {
+				t.Fatalf("expected first item to be '%s', got '%s'", tt.out.first, projects[0].ID)
+			}
+			if requests != tt.out.requests {
+				t.Fatalf("expected %d requests, got %d", tt.out.requests, requests)
+			}
+		})
+	}
+}
+
+func TestIteratorStopsEarly(t *testing.T) {
+	requests := 0
+	ts := pagedServer(1000, &requests)
+	defer ts.Close()
+	client := New("")
+	client.baseURL = ts.URL + "/"
+
+	it := client.IterateProjects(ListOptions{})
+	for i := 0; i < 150 && it.Next(); i++ {
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests)
+	}
+	if total, ok := it.Total(); !ok || total != 1000 {
+		t.Fatalf("expected total of 1000, got %d", total)
+	}
+}
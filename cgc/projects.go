@@ -1,51 +1,161 @@
 package cgc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
+// Permissions struct represents the permissions a user has in a project.
+type Permissions struct {
+	Read    bool `json:"read"`
+	Copy    bool `json:"copy"`
+	Write   bool `json:"write"`
+	Execute bool `json:"execute"`
+	Admin   bool `json:"admin"`
+}
+
 // Project struct represents the project information returned from CGC API.
 type Project struct {
-	Href string `json:"href"`
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	Href         string                 `json:"href"`
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+	Description  string                 `json:"description"`
+	BillingGroup string                 `json:"billing_group"`
+	Tags         []string               `json:"tags"`
+	Settings     map[string]interface{} `json:"settings"`
+	CreatedBy    string                 `json:"created_by"`
+	CreatedOn    time.Time              `json:"created_on"`
+	ModifiedOn   time.Time              `json:"modified_on"`
+	Permissions  Permissions            `json:"permissions"`
 }
 
 // Projects lists all the projects that belong to the token holder.
 func (c Client) Projects() ([]Project, error) {
+	projects, err := collect(c.IterateProjects(ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching projects failed: %s", err.Error())
+	}
+	return projects, nil
+}
+
+// IterateProjects returns an iterator over the projects that belong to the token holder.
+func (c Client) IterateProjects(opts ListOptions) *Iterator[Project] {
 	u := mustParseURL(c.baseURL)
 	u.Path += "projects"
+	return newIterator[Project](c, u, opts)
+}
 
-	projects := make([]Project, 0)
+// StatProject gets the details of the project that has the ID of projectID.
+func (c Client) StatProject(projectID string) (Project, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("projects/%s", projectID)
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return Project{}, fmt.Errorf("fetching project details failed: %s", err.Error())
+	}
+	defer resp.Close()
 
-	// if there's more projects than returned by default by the API, links array will
-	// be provided. The object that has the 'rel' field with the value of 'next' will
-	// also contain the 'href' with the complete link to the next page.
-	for u != nil {
-		resp, err := c.request(http.MethodGet, u, nil)
-		if err != nil {
-			return nil, fmt.Errorf("fetching files failed: %s", err.Error())
-		}
-		defer resp.Close()
+	var project Project
+	if err := json.NewDecoder(resp).Decode(&project); err != nil {
+		return Project{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
 
-		var r struct {
-			apiOKResponseTemplate
-			Projects []Project `json:"items"`
-		}
-		if err := json.NewDecoder(resp).Decode(&r); err != nil {
-			return nil, fmt.Errorf("unmarshalling response failed: %s", err.Error())
-		}
-		projects = append(projects, r.Projects...)
+	return project, nil
+}
+
+// CreateProject creates a new project out of the name, billing group, description, tags and settings of the
+// given project. Other fields are set by the API. Returns the project as it was created.
+func (c Client) CreateProject(project Project) (Project, error) {
+	body := struct {
+		Name         string                 `json:"name"`
+		BillingGroup string                 `json:"billing_group"`
+		Description  string                 `json:"description,omitempty"`
+		Tags         []string               `json:"tags,omitempty"`
+		Settings     map[string]interface{} `json:"settings,omitempty"`
+	}{project.Name, project.BillingGroup, project.Description, project.Tags, project.Settings}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return Project{}, fmt.Errorf("encoding project failed: %s", err.Error())
+	}
 
-		u = nil
-		for _, link := range r.Links {
-			if link.Rel == "next" {
-				u = mustParseURL(link.Href)
-			}
+	u := mustParseURL(c.baseURL)
+	u.Path += "projects"
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return Project{}, fmt.Errorf("creating project failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var created Project
+	if err := json.NewDecoder(resp).Decode(&created); err != nil {
+		return Project{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return created, nil
+}
+
+// UpdateProject updates the project that has the ID of projectID. Updates slice represent strings with the format
+// like 'key=value' or 'settings.key=value', all of which are sent in a single PATCH request. Returns the project as
+// it is after the update.
+func (c Client) UpdateProject(projectID string, updates []string) (Project, error) {
+	encoded, err := projectUpdatesToJSON(updates)
+	if err != nil {
+		return Project{}, fmt.Errorf("encoding update strings to JSON failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("projects/%s", projectID)
+	resp, err := c.request(http.MethodPatch, u, bytes.NewReader(encoded))
+	if err != nil {
+		return Project{}, fmt.Errorf("updating project failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var project Project
+	if err := json.NewDecoder(resp).Decode(&project); err != nil {
+		return Project{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return project, nil
+}
+
+// DeleteProject deletes the project that has the ID of projectID, along with all of its files.
+func (c Client) DeleteProject(projectID string) error {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("projects/%s", projectID)
+	resp, err := c.request(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("deleting project failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	return nil
+}
+
+// projectUpdatesToJSON parses strings in a format of 'key=value' and 'settings.key=value' and encodes them all in a
+// single JSON object, with the settings nested under the 'settings' key.
+func projectUpdatesToJSON(updates []string) ([]byte, error) {
+	toEncode := make(map[string]interface{})
+	settings := make(map[string]interface{})
+	for _, update := range updates {
+		key, value, _, err := parseUpdateString(update)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(key, "settings.") {
+			settings[strings.TrimPrefix(key, "settings.")] = value
+		} else {
+			toEncode[key] = value
 		}
 	}
+	if len(settings) > 0 {
+		toEncode["settings"] = settings
+	}
 
-	return projects, nil
+	return json.Marshal(toEncode)
 }
@@ -4,14 +4,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
 var testItems = []Project{
-	Project{"asdf", "fdsa", "zcvb"},
-	Project{"asdf", "fdsa", "zcvb"},
-	Project{"asdf", "fdsa", "zcvb"},
-	Project{"asdf", "fdsa", "zcvb"},
+	Project{Href: "asdf", ID: "fdsa", Name: "zcvb"},
+	Project{Href: "asdf", ID: "fdsa", Name: "zcvb"},
+	Project{Href: "asdf", ID: "fdsa", Name: "zcvb"},
+	Project{Href: "asdf", ID: "fdsa", Name: "zcvb"},
 }
 
 func handleProjects(w http.ResponseWriter, r *http.Request) {
@@ -45,3 +46,78 @@ func TestProjects(t *testing.T) {
 		t.Fatalf("expected %d projects, got %d", len(testItems), len(projects))
 	}
 }
+
+func handleProject(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var p Project
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		p.ID = "user/" + p.Name
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(&p); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	case http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestCreateDeleteProject(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleProject)))
+	defer ts.Close()
+
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	project, err := client.CreateProject(Project{Name: "foo", BillingGroup: "bar"})
+	if err != nil {
+		t.Fatalf("creating project errored: %s", err.Error())
+	}
+	if project.ID != "user/foo" || project.BillingGroup != "bar" {
+		t.Fatalf("unexpected project created: %+v", project)
+	}
+
+	if err := client.DeleteProject(project.ID); err != nil {
+		t.Fatalf("deleting project errored: %s", err.Error())
+	}
+}
+
+func TestProjectUpdatesToJSON(t *testing.T) {
+	type out struct {
+		body string
+		err  bool
+	}
+	td := []struct {
+		label   string
+		updates []string
+		out     out
+	}{
+		{"Fields", []string{"name=foo", "description=bar"}, out{`{"description":"bar","name":"foo"}`, false}},
+		{"Settings", []string{"settings.locked=true", "settings.use_interruptible_instances=false"}, out{
+			`{"settings":{"locked":true,"use_interruptible_instances":false}}`, false,
+		}},
+		{"Malformed", []string{"name"}, out{"", true}},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			body, err := projectUpdatesToJSON(tt.updates)
+			if err != nil {
+				if tt.out.err {
+					return
+				}
+				t.Fatalf("expected no error, got '%v'", err)
+			}
+			if strings.TrimSpace(string(body)) != tt.out.body {
+				t.Fatalf("expected '%s', got '%s'", tt.out.body, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package cgc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// fileIDPattern matches the IDs the API gives to files and folders.
+var fileIDPattern = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// ResolveProject returns the ID of the project referenced by ref, which is either the ID of a project (in the
+// 'owner/project-slug' format), its slug or its name. IDs are returned as they are, while slugs and names are looked
+// up among the projects of the token holder. It's an error if no project or more than one project matches.
+func (c Client) ResolveProject(ref string) (string, error) {
+	if strings.Count(ref, "/") == 1 {
+		return ref, nil
+	}
+
+	projects, err := c.Projects()
+	if err != nil {
+		return "", err
+	}
+	matches := make([]string, 0)
+	for _, project := range projects {
+		slug := project.ID[strings.Index(project.ID, "/")+1:]
+		if project.Name == ref || slug == ref {
+			matches = append(matches, project.ID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no project named '%s'", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("project name '%s' is ambiguous, it matches: %s", ref, strings.Join(matches, ", "))
+	}
+}
+
+// ResolveFile returns the ID of the file referenced by ref, which is either the ID of a file or its path in the
+// 'owner/project-slug/path/to/name' format. Paths are resolved by walking the folders of the project. It's an error
+// if no file or more than one file matches.
+func (c Client) ResolveFile(ref string) (string, error) {
+	if fileIDPattern.MatchString(ref) {
+		return ref, nil
+	}
+
+	parts := strings.Split(strings.Trim(ref, "/"), "/")
+	if len(parts) < 3 {
+		return "", fmt.Errorf("'%s' is neither a file ID nor a path in 'owner/project/path/to/name' format", ref)
+	}
+	projectID := parts[0] + "/" + parts[1]
+	names := parts[2:]
+
+	var entry File
+	for i, name := range names {
+		var entries []File
+		var err error
+		if i == 0 {
+			entries, err = c.FilterFiles(projectID, FileFilter{Name: name})
+		} else {
+			entries, err = c.folderEntries(entry.ID, name)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		path := strings.Join(parts[:i+3], "/")
+		switch {
+		case len(entries) == 0:
+			return "", fmt.Errorf("no file '%s'", path)
+		case len(entries) > 1:
+			return "", fmt.Errorf("path '%s' is ambiguous, it matches %d files", path, len(entries))
+		}
+		entry = entries[0]
+		if i < len(names)-1 && entry.Type != FileTypeFolder {
+			return "", fmt.Errorf("'%s' is not a folder", path)
+		}
+	}
+
+	return entry.ID, nil
+}
+
+// folderEntries lists the entries directly under the folder with folderID that have the given name.
+func (c Client) folderEntries(folderID, name string) ([]File, error) {
+	entries, err := collect(c.IterateFolder(folderID, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching folder contents failed: %s", err.Error())
+	}
+	matches := make([]File, 0, 1)
+	for _, entry := range entries {
+		if entry.Name == name {
+			matches = append(matches, entry)
+		}
+	}
+	return matches, nil
+}
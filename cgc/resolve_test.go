@@ -0,0 +1,105 @@
+package cgc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testFolderID = "5f0c1a2b3c4d5e6f7a8b9c0d"
+	testBamID    = "5f0c1a2b3c4d5e6f7a8b9c0e"
+)
+
+func handleResolve(w http.ResponseWriter, r *http.Request) {
+	var resp struct {
+		apiOKResponseTemplate
+		Items []File `json:"items"`
+	}
+	switch r.URL.Path {
+	case "/projects":
+		projects := struct {
+			apiOKResponseTemplate
+			Items []Project `json:"items"`
+		}{}
+		projects.Items = []Project{
+			{ID: "ana/rnaseq", Name: "RNA-seq"},
+			{ID: "ana/wgs", Name: "Shared"},
+			{ID: "bob/wgs", Name: "Shared"},
+		}
+		if err := json.NewEncoder(w).Encode(&projects); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	case "/files":
+		if r.URL.Query().Get("project") == "ana/rnaseq" && r.URL.Query().Get("name") == "data" {
+			resp.Items = []File{{ID: testFolderID, Name: "data", Type: FileTypeFolder}}
+		}
+	case "/files/" + testFolderID + "/list":
+		resp.Items = []File{
+			{ID: testBamID, Name: "a.bam", Type: FileTypeFile},
+			{ID: "1", Name: "dup.bam", Type: FileTypeFile},
+			{ID: "2", Name: "dup.bam", Type: FileTypeFile},
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleResolve)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	type out struct {
+		id  string
+		err error
+	}
+	td := []struct {
+		label   string
+		resolve func(string) (string, error)
+		ref     string
+		out     out
+	}{
+		{"Project ID", client.ResolveProject, "bob/other", out{"bob/other", nil}},
+		{"Project name", client.ResolveProject, "RNA-seq", out{"ana/rnaseq", nil}},
+		{"Project slug", client.ResolveProject, "rnaseq", out{"ana/rnaseq", nil}},
+		{"Ambiguous project", client.ResolveProject, "Shared", out{"", errors.New("ambiguous, it matches: ana/wgs")}},
+		{"Unknown project", client.ResolveProject, "exome", out{"", errors.New("no project named")}},
+		{"File ID", client.ResolveFile, testBamID, out{testBamID, nil}},
+		{"File path", client.ResolveFile, "ana/rnaseq/data/a.bam", out{testBamID, nil}},
+		{"Folder path", client.ResolveFile, "/ana/rnaseq/data/", out{testFolderID, nil}},
+		{"Missing file", client.ResolveFile, "ana/rnaseq/data/b.bam", out{"", errors.New("no file 'ana/rnaseq/data/b.bam'")}},
+		{"Ambiguous file", client.ResolveFile, "ana/rnaseq/data/dup.bam", out{"", errors.New("matches 2 files")}},
+		{"Not a folder", client.ResolveFile, "ana/rnaseq/data/a.bam/x", out{"", errors.New("is not a folder")}},
+		{"Malformed path", client.ResolveFile, "a.bam", out{"", errors.New("neither a file ID nor a path")}},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			id, err := tt.resolve(tt.ref)
+			if err != nil {
+				if tt.out.err == nil || !strings.Contains(err.Error(), tt.out.err.Error()) {
+					t.Fatalf("expected '%v', got '%v'", tt.out.err, err)
+				}
+				return
+			}
+			if tt.out.err != nil {
+				t.Fatalf("expected '%v', got no error", tt.out.err)
+			}
+			if id != tt.out.id {
+				t.Fatalf("expected '%s', got '%s'", tt.out.id, id)
+			}
+		})
+	}
+}
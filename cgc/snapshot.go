@@ -0,0 +1,147 @@
+package cgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SnapshotEntry is a file or a folder of a project as recorded in a snapshot, along with its path in the project
+// (e.g. 'reads/sample1.fastq'). Files hold their full details, the same ones StatFile returns, unless fetching them
+// failed, in which case Error says why and the entry only has what the listing had.
+type SnapshotEntry struct {
+	Path  string `json:"path"`
+	Error string `json:"error,omitempty"`
+	File
+}
+
+// Snapshot is a record of every file and folder of a project taken at some point in time, which can be searched
+// without access to the API.
+type Snapshot struct {
+	Project Project         `json:"project"`
+	TakenOn time.Time       `json:"taken_on"`
+	Entries []SnapshotEntry `json:"entries"`
+}
+
+// SnapshotProject records every file and folder of the project with projectID, walking all of its folders. The
+// details of the files are fetched with bulk requests, since listings don't include all of them. A file whose
+// details couldn't be fetched doesn't stop the snapshot, its entry reports the error instead.
+func (c Client) SnapshotProject(projectID string) (Snapshot, error) {
+	project, err := c.StatProject(projectID)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot := Snapshot{Project: project, TakenOn: time.Now().UTC(), Entries: make([]SnapshotEntry, 0)}
+
+	// folders are walked breadth first, the root of the project is the only one without an ID
+	type folder struct {
+		id   string
+		path string
+	}
+	queue := []folder{{}}
+	fileIDs := make([]string, 0)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		var it *Iterator[File]
+		if current.id == "" {
+			it = c.IterateFiles(projectID, FileFilter{}, ListOptions{})
+		} else {
+			it = c.IterateFolder(current.id, ListOptions{})
+		}
+		for it.Next() {
+			entry := SnapshotEntry{Path: current.path + it.Item().Name, File: it.Item()}
+			if entry.Type == FileTypeFolder {
+				queue = append(queue, folder{entry.ID, entry.Path + "/"})
+			} else {
+				fileIDs = append(fileIDs, entry.ID)
+			}
+			snapshot.Entries = append(snapshot.Entries, entry)
+		}
+		if err := it.Err(); err != nil {
+			return Snapshot{}, fmt.Errorf("listing '%s' failed: %s", current.path, err.Error())
+		}
+	}
+
+	results := c.StatFiles(fileIDs)
+	details := make(map[string]FileActionResult, len(results))
+	for _, r := range results {
+		details[r.FileID] = r
+	}
+	for i, entry := range snapshot.Entries {
+		r, ok := details[entry.ID]
+		switch {
+		case !ok:
+		case r.Err != nil:
+			snapshot.Entries[i].Error = r.Err.Error()
+		default:
+			snapshot.Entries[i].File = r.File
+		}
+	}
+
+	return snapshot, nil
+}
+
+// List returns the entries at the root of the project that match the filter, the same ones FilterFiles returns.
+func (s Snapshot) List(filter FileFilter) []SnapshotEntry {
+	entries := make([]SnapshotEntry, 0)
+	for _, entry := range s.Entries {
+		if !strings.Contains(entry.Path, "/") && filter.Match(entry.File) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Find returns the entries anywhere in the project that match the filter.
+func (s Snapshot) Find(filter FileFilter) []SnapshotEntry {
+	entries := make([]SnapshotEntry, 0)
+	for _, entry := range s.Entries {
+		if filter.Match(entry.File) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Save writes the snapshot to the file at path as JSON. The snapshot is written to a temporary file next to it first,
+// so an existing snapshot at path is only replaced once the new one is complete.
+func (s Snapshot) Save(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating '%s' file failed: %s", path, err.Error())
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(s); err != nil {
+		return fmt.Errorf("writing snapshot to '%s' failed: %s", path, err.Error())
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("syncing '%s' file failed: %s", path, err.Error())
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing '%s' file failed: %s", path, err.Error())
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("replacing '%s' file failed: %s", path, err.Error())
+	}
+	return nil
+}
+
+// LoadSnapshot reads the snapshot saved in the file at path.
+func LoadSnapshot(path string) (Snapshot, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("reading '%s' file failed: %s", path, err.Error())
+	}
+	var s Snapshot
+	if err := json.Unmarshal(bs, &s); err != nil {
+		return Snapshot{}, fmt.Errorf("unmarshalling snapshot failed: %s", err.Error())
+	}
+	return s, nil
+}
@@ -0,0 +1,143 @@
+package cgc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// snapshotFiles are the files of the project served by handleSnapshot, with their full details. Listings only have
+// the names, types and IDs.
+var snapshotFiles = map[string]File{
+	"f1": {ID: "f1", Name: "reads", Type: FileTypeFolder},
+	"f2": {ID: "f2", Name: "sample1.fastq", Type: FileTypeFile, Parent: "f1", Tags: []string{"raw"},
+		Metadata: map[string]interface{}{"sample_id": "s1"}},
+	"f3": {ID: "f3", Name: "nested", Type: FileTypeFolder, Parent: "f1"},
+	"f4": {ID: "f4", Name: "sample2.fastq", Type: FileTypeFile, Parent: "f3", Tags: []string{"raw"},
+		Metadata: map[string]interface{}{"sample_id": "s2"}},
+	"f5": {ID: "f5", Name: "report.html", Type: FileTypeFile, Tags: []string{"report"}},
+	// the details of f6 can't be fetched
+	"f6": {ID: "f6", Name: "secret.bam", Type: FileTypeFile, Parent: "f3"},
+}
+
+func handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	listing := func(parent string) []File {
+		files := make([]File, 0)
+		for _, id := range []string{"f1", "f2", "f3", "f4", "f5", "f6"} {
+			if f := snapshotFiles[id]; f.Parent == parent {
+				files = append(files, File{ID: f.ID, Name: f.Name, Type: f.Type, Parent: f.Parent})
+			}
+		}
+		return files
+	}
+
+	var resp interface{}
+	switch {
+	case r.URL.Path == "/projects/owner/project":
+		resp = Project{ID: "owner/project", Name: "Project"}
+	case r.URL.Path == "/files" && r.URL.Query().Get("project") == "owner/project":
+		resp = map[string][]File{"items": listing("")}
+	case r.URL.Path == "/files/f1/list":
+		resp = map[string][]File{"items": listing("f1")}
+	case r.URL.Path == "/files/f3/list":
+		resp = map[string][]File{"items": listing("f3")}
+	case r.Method == http.MethodPost && r.URL.Path == "/bulk/files/get":
+		var req struct {
+			FileIDs []string `json:"file_ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		items := make([]bulkItem[File], 0)
+		for _, id := range req.FileIDs {
+			if id == "f6" {
+				items = append(items, bulkItem[File]{Error: &apiErrorResponseTemplate{"access denied"}})
+				continue
+			}
+			items = append(items, bulkItem[File]{Resource: snapshotFiles[id]})
+		}
+		resp = map[string]interface{}{"items": items}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleSnapshot)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	snapshot, err := client.SnapshotProject("owner/project")
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if snapshot.Project.Name != "Project" || len(snapshot.Entries) != len(snapshotFiles) {
+		t.Fatalf("expected the whole project, got %+v", snapshot)
+	}
+
+	for _, entry := range snapshot.Entries {
+		if (entry.ID == "f6") != (entry.Error != "") {
+			t.Fatalf("expected only 'f6' to have an error, got %+v", entry)
+		}
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "snap.json")
+	for i := 0; i < 2; i++ {
+		if err := snapshot.Save(path); err != nil {
+			t.Fatalf("expected no error, got '%v'", err)
+		}
+	}
+	if names, err := os.ReadDir(dir); err != nil || len(names) != 1 {
+		t.Fatalf("expected only the snapshot in the directory, got %v, '%v'", names, err)
+	}
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+
+	paths := func(entries []SnapshotEntry) []string {
+		ps := make([]string, 0)
+		for _, e := range entries {
+			ps = append(ps, e.Path)
+		}
+		return ps
+	}
+	td := []struct {
+		label  string
+		find   bool
+		filter FileFilter
+		out    []string
+	}{
+		{"List root", false, FileFilter{}, []string{"reads", "report.html"}},
+		{"List by tag", false, FileFilter{Tags: []string{"raw"}}, []string{}},
+		{"Find by tag", true, FileFilter{Tags: []string{"raw"}}, []string{"reads/sample1.fastq",
+			"reads/nested/sample2.fastq"}},
+		{"Find by metadata", true, FileFilter{Metadata: map[string]string{"sample_id": "s2"}},
+			[]string{"reads/nested/sample2.fastq"}},
+		{"Find by name", true, FileFilter{Name: "nested"}, []string{"reads/nested"}},
+	}
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			entries := loaded.List(tt.filter)
+			if tt.find {
+				entries = loaded.Find(tt.filter)
+			}
+			if got := paths(entries); !reflect.DeepEqual(got, tt.out) {
+				t.Fatalf("expected %v, got %v", tt.out, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package cgc
+
+// AddTags returns the tags with every tag from add appended to them, unless it's already present. The order of
+// existing tags is preserved.
+func AddTags(tags, add []string) []string {
+	result := append(make([]string, 0, len(tags)+len(add)), tags...)
+	for _, tag := range add {
+		if !contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// RemoveTags returns the tags without any of the tags from remove. The order of remaining tags is preserved.
+func RemoveTags(tags, remove []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !contains(remove, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// DiffTags returns the tags that are present in after but not in before, and the ones present in before but not
+// in after.
+func DiffTags(before, after []string) (added []string, removed []string) {
+	for _, tag := range after {
+		if !contains(before, tag) {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range before {
+		if !contains(after, tag) {
+			removed = append(removed, tag)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,39 @@
+package cgc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddRemoveTags(t *testing.T) {
+	tags := []string{"foo", "bar"}
+
+	added := AddTags(tags, []string{"bar", "baz"})
+	if !reflect.DeepEqual(added, []string{"foo", "bar", "baz"}) {
+		t.Fatalf("expected '[foo bar baz]', got '%v'", added)
+	}
+
+	removed := RemoveTags(added, []string{"foo", "qux"})
+	if !reflect.DeepEqual(removed, []string{"bar", "baz"}) {
+		t.Fatalf("expected '[bar baz]', got '%v'", removed)
+	}
+
+	if !reflect.DeepEqual(tags, []string{"foo", "bar"}) {
+		t.Fatalf("expected original tags to be untouched, got '%v'", tags)
+	}
+}
+
+func TestDiffTags(t *testing.T) {
+	added, removed := DiffTags([]string{"foo", "bar"}, []string{"bar", "baz"})
+	if !reflect.DeepEqual(added, []string{"baz"}) {
+		t.Fatalf("expected '[baz]' to be added, got '%v'", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"foo"}) {
+		t.Fatalf("expected '[foo]' to be removed, got '%v'", removed)
+	}
+
+	added, removed = DiffTags([]string{"foo"}, []string{"foo"})
+	if len(added) != 0 || len(removed) != 0 {
+		t.Fatalf("expected no changes, got '%v' added and '%v' removed", added, removed)
+	}
+}
@@ -0,0 +1,222 @@
+package cgc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Statuses a task can be in.
+const (
+	TaskDraft     = "DRAFT"
+	TaskCreating  = "CREATING"
+	TaskQueued    = "QUEUED"
+	TaskRunning   = "RUNNING"
+	TaskCompleted = "COMPLETED"
+	TaskFailed    = "FAILED"
+	TaskAborted   = "ABORTED"
+)
+
+// ExecutionStatus struct represents the progress of a task execution returned from CGC API.
+type ExecutionStatus struct {
+	Message        string `json:"message"`
+	StepsCompleted int    `json:"steps_completed"`
+	StepsTotal     int    `json:"steps_total"`
+	Queued         int    `json:"queued"`
+	Running        int    `json:"running"`
+	Completed      int    `json:"completed"`
+	Failed         int    `json:"failed"`
+	Aborted        int    `json:"aborted"`
+	Duration       int64  `json:"duration"`
+	SystemLimit    bool   `json:"system_limit"`
+	AccountLimit   bool   `json:"account_limit"`
+}
+
+// BatchBy struct represents the criteria used to split the batch input of a batch task.
+type BatchBy struct {
+	Type     string   `json:"type"`
+	Criteria []string `json:"criteria,omitempty"`
+}
+
+// Task struct represents the task information returned from CGC API.
+type Task struct {
+	Href            string                 `json:"href"`
+	ID              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description"`
+	Status          string                 `json:"status"`
+	Project         string                 `json:"project"`
+	App             string                 `json:"app"`
+	Type            string                 `json:"type"`
+	CreatedBy       string                 `json:"created_by"`
+	ExecutedBy      string                 `json:"executed_by"`
+	CreatedTime     time.Time              `json:"created_time"`
+	StartTime       time.Time              `json:"start_time"`
+	EndTime         time.Time              `json:"end_time"`
+	Batch           bool                   `json:"batch"`
+	BatchInput      string                 `json:"batch_input,omitempty"`
+	BatchBy         *BatchBy               `json:"batch_by,omitempty"`
+	Parent          string                 `json:"parent,omitempty"`
+	Inputs          map[string]interface{} `json:"inputs"`
+	Outputs         map[string]interface{} `json:"outputs"`
+	ExecutionStatus *ExecutionStatus       `json:"execution_status,omitempty"`
+}
+
+// Done reports whether the task reached one of the final statuses.
+func (t Task) Done() bool {
+	return t.Status == TaskCompleted || t.Status == TaskFailed || t.Status == TaskAborted
+}
+
+// TaskFilter narrows down the listed tasks. Empty fields don't filter anything.
+type TaskFilter struct {
+	Project string
+	Status  string
+	Parent  string
+}
+
+// FileInput returns the value of a task input that references the file with fileID.
+func FileInput(fileID string) map[string]interface{} {
+	return map[string]interface{}{
+		"class": "File",
+		"path":  fileID,
+	}
+}
+
+// Tasks lists all the tasks that match the filter.
+func (c Client) Tasks(filter TaskFilter) ([]Task, error) {
+	tasks, err := collect(c.IterateTasks(filter, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching tasks failed: %s", err.Error())
+	}
+	return tasks, nil
+}
+
+// IterateTasks returns an iterator over the tasks that match the filter.
+func (c Client) IterateTasks(filter TaskFilter, opts ListOptions) *Iterator[Task] {
+	u := mustParseURL(c.baseURL)
+	u.Path += "tasks"
+	params := url.Values{}
+	if filter.Project != "" {
+		params.Add("project", filter.Project)
+	}
+	if filter.Status != "" {
+		params.Add("status", filter.Status)
+	}
+	if filter.Parent != "" {
+		params.Add("parent", filter.Parent)
+	}
+	u.RawQuery = params.Encode()
+	return newIterator[Task](c, u, opts)
+}
+
+// StatTask gets the details of the task that has the ID of taskID.
+func (c Client) StatTask(taskID string) (Task, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("tasks/%s", taskID)
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return Task{}, fmt.Errorf("fetching task details failed: %w", err)
+	}
+	defer resp.Close()
+
+	var task Task
+	if err := json.NewDecoder(resp).Decode(&task); err != nil {
+		return Task{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return task, nil
+}
+
+// CreateTask creates a draft task out of the name, description, project, app, inputs and batch settings of the
+// given task. Other fields are set by the API. If run is true, the task is run right after it's created. Returns
+// the task as it was created.
+func (c Client) CreateTask(task Task, run bool) (Task, error) {
+	body := struct {
+		Name        string                 `json:"name,omitempty"`
+		Description string                 `json:"description,omitempty"`
+		Project     string                 `json:"project"`
+		App         string                 `json:"app"`
+		Inputs      map[string]interface{} `json:"inputs"`
+		BatchInput  string                 `json:"batch_input,omitempty"`
+		BatchBy     *BatchBy               `json:"batch_by,omitempty"`
+	}{task.Name, task.Description, task.Project, task.App, task.Inputs, task.BatchInput, task.BatchBy}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return Task{}, fmt.Errorf("encoding task failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += "tasks"
+	if run {
+		u.RawQuery = url.Values{"action": {"run"}}.Encode()
+	}
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return Task{}, fmt.Errorf("creating task failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var created Task
+	if err := json.NewDecoder(resp).Decode(&created); err != nil {
+		return Task{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return created, nil
+}
+
+// RunTask runs the draft task that has the ID of taskID.
+func (c Client) RunTask(taskID string) (Task, error) {
+	return c.taskAction(taskID, "run")
+}
+
+// AbortTask aborts the queued or running task that has the ID of taskID.
+func (c Client) AbortTask(taskID string) (Task, error) {
+	return c.taskAction(taskID, "abort")
+}
+
+// CloneTask creates a new draft task with the same app, inputs and settings as the task that has the ID of taskID.
+func (c Client) CloneTask(taskID string) (Task, error) {
+	return c.taskAction(taskID, "clone")
+}
+
+// taskAction performs an action on the task that has the ID of taskID and returns the task the action resulted in.
+func (c Client) taskAction(taskID, action string) (Task, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("tasks/%s/actions/%s", taskID, action)
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return Task{}, fmt.Errorf("task action '%s' failed: %s", action, err.Error())
+	}
+	defer resp.Close()
+
+	var task Task
+	if err := json.NewDecoder(resp).Decode(&task); err != nil {
+		return Task{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return task, nil
+}
+
+// WaitTask blocks until the task that has the ID of taskID reaches one of the final statuses or the context is done,
+// polling the task with backoff. Every time the status or the execution progress of the task changes, onChange is
+// called with the task. Returns the last fetched state of the task, along with the context error if the context was
+// done first.
+func (c Client) WaitTask(ctx context.Context, taskID string, onChange func(Task)) (Task, error) {
+	// the task is polled for changes, so it can't come from the cache
+	return waitUntilDone(ctx, taskID, c.WithCache(nil).StatTask, taskChanged, Task.Done, onChange)
+}
+
+// taskChanged reports whether the status or the execution progress differs between the two states of a task.
+func taskChanged(before, after Task) bool {
+	if before.Status != after.Status {
+		return true
+	}
+	if before.ExecutionStatus == nil || after.ExecutionStatus == nil {
+		return before.ExecutionStatus != after.ExecutionStatus
+	}
+	return *before.ExecutionStatus != *after.ExecutionStatus
+}
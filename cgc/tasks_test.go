@@ -0,0 +1,221 @@
+package cgc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTaskID = "testTaskID"
+
+func handleTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "tasks") {
+		var task Task
+		if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		task.ID = testTaskID
+		task.Status = TaskDraft
+		if r.URL.Query().Get("action") == "run" {
+			task.Status = TaskQueued
+		}
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(&task); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s := regexp.MustCompile(`tasks/([^/]+)(/actions/([^/]+))?$`).FindStringSubmatch(r.URL.Path)
+	if len(s) != 4 || s[1] != testTaskID {
+		w.WriteHeader(http.StatusNotFound)
+		resp := apiErrorResponseTemplate{
+			Message: fmt.Sprintf("Task '%s' not found", r.URL.Path),
+		}
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	task := Task{ID: testTaskID, Status: TaskDraft}
+	switch s[3] {
+	case "run":
+		task.Status = TaskQueued
+	case "abort":
+		task.Status = TaskAborted
+	case "clone":
+		task.ID = "clonedTaskID"
+	}
+	if err := json.NewEncoder(w).Encode(&task); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestCreateTask(t *testing.T) {
+	type in struct {
+		run bool
+	}
+	type out struct {
+		status string
+	}
+	td := []struct {
+		label string
+		in    in
+		out   out
+	}{
+		{"Draft", in{false}, out{TaskDraft}},
+		{"Run", in{true}, out{TaskQueued}},
+	}
+
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleTasks)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			task, err := client.CreateTask(Task{
+				Project: testProjectID,
+				App:     "user/project/app",
+				Inputs:  map[string]interface{}{"reads": FileInput(testFileID)},
+			}, tt.in.run)
+			if err != nil {
+				t.Fatalf("expected no error, got '%v'", err)
+			}
+			if task.Status != tt.out.status {
+				t.Fatalf("expected status '%s', got '%s'", tt.out.status, task.Status)
+			}
+			reads, ok := task.Inputs["reads"].(map[string]interface{})
+			if !ok || reads["path"] != testFileID {
+				t.Fatalf("expected file input, got '%v'", task.Inputs["reads"])
+			}
+		})
+	}
+}
+
+func TestTaskActions(t *testing.T) {
+	type out struct {
+		id     string
+		status string
+		err    error
+	}
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleTasks)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	td := []struct {
+		label  string
+		action func(string) (Task, error)
+		taskID string
+		out    out
+	}{
+		{"Stat", client.StatTask, testTaskID, out{testTaskID, TaskDraft, nil}},
+		{"Run", client.RunTask, testTaskID, out{testTaskID, TaskQueued, nil}},
+		{"Abort", client.AbortTask, testTaskID, out{testTaskID, TaskAborted, nil}},
+		{"Clone", client.CloneTask, testTaskID, out{"clonedTaskID", TaskDraft, nil}},
+		{"Wrong Task ID", client.RunTask, "wrong", out{"", "", errors.New("not found")}},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			task, err := tt.action(tt.taskID)
+			if err != nil {
+				if tt.out.err != nil {
+					if !strings.Contains(err.Error(), tt.out.err.Error()) {
+						t.Fatalf("expected '%v', got '%v'", tt.out.err, err)
+					}
+					return
+				}
+				t.Fatalf("expected no error, got '%v'", err)
+			}
+			if task.ID != tt.out.id || task.Status != tt.out.status {
+				t.Fatalf("expected task '%s' in '%s', got '%s' in '%s'", tt.out.id, tt.out.status, task.ID, task.Status)
+			}
+		})
+	}
+}
+
+// setWaitIntervals shortens the intervals between polls for the duration of the test.
+func setWaitIntervals(t *testing.T) {
+	minInterval, maxInterval := waitMinInterval, waitMaxInterval
+	t.Cleanup(func() { waitMinInterval, waitMaxInterval = minInterval, maxInterval })
+	waitMinInterval, waitMaxInterval = time.Millisecond, 4*time.Millisecond
+}
+
+func TestWaitTask(t *testing.T) {
+	setWaitIntervals(t)
+
+	// an empty status makes the server fail the poll with the status code in code
+	statuses := []string{TaskQueued, "", TaskQueued, TaskRunning, "", "", TaskRunning, TaskRunning, TaskCompleted}
+	code := http.StatusServiceUnavailable
+	polls, requests := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		task := Task{ID: testTaskID, Status: statuses[polls]}
+		if polls < len(statuses)-1 {
+			polls++
+		}
+		if task.Status == "" {
+			w.WriteHeader(code)
+			fmt.Fprint(w, `{"message": "unavailable"}`)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(&task); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+	client := New("")
+	client.baseURL = ts.URL + "/"
+
+	changes := make([]string, 0)
+	task, err := client.WaitTask(context.Background(), testTaskID, func(t Task) {
+		changes = append(changes, t.Status)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if task.Status != TaskCompleted {
+		t.Fatalf("expected task to be completed, got '%s'", task.Status)
+	}
+	if strings.Join(changes, ",") != "QUEUED,RUNNING,COMPLETED" {
+		t.Fatalf("expected 'QUEUED,RUNNING,COMPLETED' changes, got '%s'", strings.Join(changes, ","))
+	}
+
+	polls = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Millisecond)
+	defer cancel()
+	statuses = []string{TaskRunning}
+	if _, err := client.WaitTask(ctx, testTaskID, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected waiting to time out, got '%v'", err)
+	}
+
+	polls, requests = 0, 0
+	statuses = []string{TaskRunning, ""}
+	if _, err := client.WaitTask(context.Background(), testTaskID, nil); err == nil {
+		t.Fatalf("expected waiting to give up after %d failed polls", waitMaxRetries)
+	}
+	if requests != 1+waitMaxRetries+1 {
+		t.Fatalf("expected %d polls, got %d", 1+waitMaxRetries+1, requests)
+	}
+
+	polls, code = 0, http.StatusNotFound
+	statuses = []string{TaskRunning, "", TaskCompleted}
+	if _, err := client.WaitTask(context.Background(), testTaskID, nil); err == nil {
+		t.Fatalf("expected waiting to stop on a 404")
+	}
+}
@@ -0,0 +1,387 @@
+package cgc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// States an import or an export job can be in.
+const (
+	TransferPending   = "PENDING"
+	TransferRunning   = "RUNNING"
+	TransferCompleted = "COMPLETED"
+	TransferFailed    = "FAILED"
+)
+
+// bulkMaxItems is the maximum number of items the bulk endpoints accept in a single request.
+const bulkMaxItems = 100
+
+// VolumeLocation struct represents an object on a volume.
+type VolumeLocation struct {
+	Volume   string `json:"volume"`
+	Location string `json:"location"`
+}
+
+// ImportDestination struct represents where an imported file ends up. Either Project or Parent (the ID of a folder)
+// is set. If Name is empty, the name of the object on the volume is used.
+type ImportDestination struct {
+	Project string `json:"project,omitempty"`
+	Parent  string `json:"parent,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+// ExportSource struct represents the file that is exported.
+type ExportSource struct {
+	File string `json:"file"`
+}
+
+// TransferError struct represents the reason an import or an export job failed.
+type TransferError struct {
+	Status  int    `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ImportRequest struct represents a request for importing an object from a volume into a project. If the file
+// already exists, it's overwritten when Overwrite is set, or the imported file gets a new name when Autorename is set.
+type ImportRequest struct {
+	Source      VolumeLocation    `json:"source"`
+	Destination ImportDestination `json:"destination"`
+	Overwrite   bool              `json:"overwrite"`
+	Autorename  bool              `json:"autorename"`
+}
+
+// Import struct represents the import job information returned from CGC API. Result holds the imported file once
+// the job is completed, while Error holds the reason the job failed.
+type Import struct {
+	Href        string            `json:"href"`
+	ID          string            `json:"id"`
+	State       string            `json:"state"`
+	Overwrite   bool              `json:"overwrite"`
+	Autorename  bool              `json:"autorename"`
+	Source      VolumeLocation    `json:"source"`
+	Destination ImportDestination `json:"destination"`
+	StartedOn   time.Time         `json:"started_on"`
+	FinishedOn  time.Time         `json:"finished_on"`
+	Result      *File             `json:"result,omitempty"`
+	Error       *TransferError    `json:"error,omitempty"`
+}
+
+// ExportRequest struct represents a request for exporting a file from a project to a volume. An existing object
+// on the volume is overwritten only if Overwrite is set.
+type ExportRequest struct {
+	Source      ExportSource   `json:"source"`
+	Destination VolumeLocation `json:"destination"`
+	Overwrite   bool           `json:"overwrite"`
+}
+
+// Export struct represents the export job information returned from CGC API. Result holds the exported file once
+// the job is completed, while Error holds the reason the job failed.
+type Export struct {
+	Href        string         `json:"href"`
+	ID          string         `json:"id"`
+	State       string         `json:"state"`
+	Overwrite   bool           `json:"overwrite"`
+	Source      ExportSource   `json:"source"`
+	Destination VolumeLocation `json:"destination"`
+	StartedOn   time.Time      `json:"started_on"`
+	FinishedOn  time.Time      `json:"finished_on"`
+	Result      *File          `json:"result,omitempty"`
+	Error       *TransferError `json:"error,omitempty"`
+}
+
+// Done reports whether the import job reached one of the final states.
+func (i Import) Done() bool {
+	return i.State == TransferCompleted || i.State == TransferFailed
+}
+
+// Done reports whether the export job reached one of the final states.
+func (e Export) Done() bool {
+	return e.State == TransferCompleted || e.State == TransferFailed
+}
+
+// ImportSubmission is the outcome of starting a single import job out of a bulk request.
+type ImportSubmission struct {
+	Request ImportRequest
+	Import  Import
+	Err     error
+}
+
+// ExportSubmission is the outcome of starting a single export job out of a bulk request.
+type ExportSubmission struct {
+	Request ExportRequest
+	Export  Export
+	Err     error
+}
+
+// TransferFilter narrows down the listed import or export jobs. Empty fields don't filter anything. Project is only
+// used for imports.
+type TransferFilter struct {
+	Volume  string
+	Project string
+	State   string
+}
+
+func (f TransferFilter) values() url.Values {
+	params := url.Values{}
+	if f.Volume != "" {
+		params.Add("volume", f.Volume)
+	}
+	if f.Project != "" {
+		params.Add("project", f.Project)
+	}
+	if f.State != "" {
+		params.Add("state", f.State)
+	}
+	return params
+}
+
+// StartImport starts a job importing an object from a volume into a project.
+func (c Client) StartImport(req ImportRequest) (Import, error) {
+	var job Import
+	if err := c.startTransfer("storage/imports", req, &job); err != nil {
+		return Import{}, fmt.Errorf("starting import failed: %s", err.Error())
+	}
+	return job, nil
+}
+
+// StatImport gets the details of the import job that has the ID of importID.
+func (c Client) StatImport(importID string) (Import, error) {
+	var job Import
+	if err := c.statTransfer(fmt.Sprintf("storage/imports/%s", importID), &job); err != nil {
+		return Import{}, fmt.Errorf("fetching import details failed: %w", err)
+	}
+	return job, nil
+}
+
+// Imports lists all the import jobs that match the filter.
+func (c Client) Imports(filter TransferFilter) ([]Import, error) {
+	jobs, err := collect(c.IterateImports(filter, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching imports failed: %s", err.Error())
+	}
+	return jobs, nil
+}
+
+// IterateImports returns an iterator over the import jobs that match the filter.
+func (c Client) IterateImports(filter TransferFilter, opts ListOptions) *Iterator[Import] {
+	u := mustParseURL(c.baseURL)
+	u.Path += "storage/imports"
+	u.RawQuery = filter.values().Encode()
+	return newIterator[Import](c, u, opts)
+}
+
+// WaitImport blocks until the import job that has the ID of importID reaches one of the final states or the context
+// is done, the same way WaitTask does for tasks. Every time the state of the job changes, onChange is called with the
+// job.
+func (c Client) WaitImport(ctx context.Context, importID string, onChange func(Import)) (Import, error) {
+	stat := c.WithCache(nil).StatImport
+	changed := func(before, after Import) bool { return before.State != after.State }
+	return waitUntilDone(ctx, importID, stat, changed, Import.Done, onChange)
+}
+
+// StartImports starts an import job for every request, splitting the requests into as many bulk requests as needed.
+// A request that couldn't be started doesn't stop the others, the outcome of each one is returned in the same order
+// the requests were given in.
+func (c Client) StartImports(reqs []ImportRequest) []ImportSubmission {
+	items := bulkRequest[ImportRequest, Import](c, "bulk/storage/imports/create", "items", reqs)
+	submissions := make([]ImportSubmission, 0, len(reqs))
+	for i, item := range items {
+		submissions = append(submissions, ImportSubmission{reqs[i], item.Resource, item.err()})
+	}
+	return submissions
+}
+
+// StatImports gets the details of all the import jobs with the given IDs using bulk requests.
+func (c Client) StatImports(importIDs []string) ([]Import, error) {
+	items := bulkRequest[string, Import](c, "bulk/storage/imports/get", "import_ids", importIDs)
+	jobs := make([]Import, 0, len(importIDs))
+	for i, item := range items {
+		if err := item.err(); err != nil {
+			return nil, fmt.Errorf("fetching import '%s' failed: %s", importIDs[i], err.Error())
+		}
+		jobs = append(jobs, item.Resource)
+	}
+	return jobs, nil
+}
+
+// StartExport starts a job exporting a file from a project to a volume.
+func (c Client) StartExport(req ExportRequest) (Export, error) {
+	var job Export
+	if err := c.startTransfer("storage/exports", req, &job); err != nil {
+		return Export{}, fmt.Errorf("starting export failed: %s", err.Error())
+	}
+	return job, nil
+}
+
+// StatExport gets the details of the export job that has the ID of exportID.
+func (c Client) StatExport(exportID string) (Export, error) {
+	var job Export
+	if err := c.statTransfer(fmt.Sprintf("storage/exports/%s", exportID), &job); err != nil {
+		return Export{}, fmt.Errorf("fetching export details failed: %w", err)
+	}
+	return job, nil
+}
+
+// Exports lists all the export jobs that match the filter.
+func (c Client) Exports(filter TransferFilter) ([]Export, error) {
+	jobs, err := collect(c.IterateExports(filter, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching exports failed: %s", err.Error())
+	}
+	return jobs, nil
+}
+
+// IterateExports returns an iterator over the export jobs that match the filter.
+func (c Client) IterateExports(filter TransferFilter, opts ListOptions) *Iterator[Export] {
+	u := mustParseURL(c.baseURL)
+	u.Path += "storage/exports"
+	filter.Project = ""
+	u.RawQuery = filter.values().Encode()
+	return newIterator[Export](c, u, opts)
+}
+
+// WaitExport blocks until the export job that has the ID of exportID reaches one of the final states or the context
+// is done, the same way WaitTask does for tasks. Every time the state of the job changes, onChange is called with the
+// job.
+func (c Client) WaitExport(ctx context.Context, exportID string, onChange func(Export)) (Export, error) {
+	stat := c.WithCache(nil).StatExport
+	changed := func(before, after Export) bool { return before.State != after.State }
+	return waitUntilDone(ctx, exportID, stat, changed, Export.Done, onChange)
+}
+
+// StartExports starts an export job for every request, splitting the requests into as many bulk requests as needed.
+// A request that couldn't be started doesn't stop the others, the outcome of each one is returned in the same order
+// the requests were given in.
+func (c Client) StartExports(reqs []ExportRequest) []ExportSubmission {
+	items := bulkRequest[ExportRequest, Export](c, "bulk/storage/exports/create", "items", reqs)
+	submissions := make([]ExportSubmission, 0, len(reqs))
+	for i, item := range items {
+		submissions = append(submissions, ExportSubmission{reqs[i], item.Resource, item.err()})
+	}
+	return submissions
+}
+
+// StatExports gets the details of all the export jobs with the given IDs using bulk requests.
+func (c Client) StatExports(exportIDs []string) ([]Export, error) {
+	items := bulkRequest[string, Export](c, "bulk/storage/exports/get", "export_ids", exportIDs)
+	jobs := make([]Export, 0, len(exportIDs))
+	for i, item := range items {
+		if err := item.err(); err != nil {
+			return nil, fmt.Errorf("fetching export '%s' failed: %s", exportIDs[i], err.Error())
+		}
+		jobs = append(jobs, item.Resource)
+	}
+	return jobs, nil
+}
+
+// startTransfer posts the request to the endpoint at path and decodes the started job into job.
+func (c Client) startTransfer(path string, req interface{}, job interface{}) error {
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encoding request failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += path
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	if err := json.NewDecoder(resp).Decode(job); err != nil {
+		return fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	return nil
+}
+
+// statTransfer fetches the job at path and decodes it into job.
+func (c Client) statTransfer(path string, job interface{}) error {
+	u := mustParseURL(c.baseURL)
+	u.Path += path
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	if err := json.NewDecoder(resp).Decode(job); err != nil {
+		return fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	return nil
+}
+
+// bulkItem is a single item of a bulk response, holding either the resource or the error it failed with.
+type bulkItem[T any] struct {
+	Resource T                         `json:"resource"`
+	Error    *apiErrorResponseTemplate `json:"error,omitempty"`
+}
+
+func (i bulkItem[T]) err() error {
+	if i.Error == nil {
+		return nil
+	}
+	return fmt.Errorf("%s", i.Error.Message)
+}
+
+// bulkRequest posts the items to the bulk endpoint at path under the key, splitting them into as many requests as
+// needed. Returns the items of the responses, one for every item posted and in the same order. A request that fails
+// doesn't stop the others, every item it carried is returned with the error instead.
+func bulkRequest[R, T any](c Client, path, key string, reqs []R) []bulkItem[T] {
+	items := make([]bulkItem[T], 0, len(reqs))
+	inChunks(len(reqs), bulkMaxItems, func(start, end int) {
+		chunk, err := bulkChunk[R, T](c, path, key, reqs[start:end])
+		if err != nil {
+			chunk = make([]bulkItem[T], end-start)
+			for i := range chunk {
+				chunk[i].Error = &apiErrorResponseTemplate{Message: err.Error()}
+			}
+		}
+		items = append(items, chunk...)
+	})
+	return items
+}
+
+// bulkChunk posts a single bulk request to the endpoint at path, holding no more than bulkMaxItems items.
+func bulkChunk[R, T any](c Client, path, key string, reqs []R) ([]bulkItem[T], error) {
+	encoded, err := json.Marshal(map[string]interface{}{key: reqs})
+	if err != nil {
+		return nil, fmt.Errorf("encoding request failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += path
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	var r struct {
+		Items []bulkItem[T] `json:"items"`
+	}
+	if err := json.NewDecoder(resp).Decode(&r); err != nil {
+		return nil, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	if len(r.Items) != len(reqs) {
+		return nil, fmt.Errorf("expected %d items in the response, got %d", len(reqs), len(r.Items))
+	}
+	return r.Items, nil
+}
+
+// inChunks calls fn with the bounds of every chunk of at most size items out of n items.
+func inChunks(n, size int, fn func(start, end int)) {
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		fn(start, end)
+	}
+}
@@ -0,0 +1,129 @@
+package cgc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func handleImports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/bulk/storage/imports/create" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var req struct {
+		Items []ImportRequest `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Items) > bulkMaxItems {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var resp struct {
+		Items []bulkItem[Import] `json:"items"`
+	}
+	for _, item := range req.Items {
+		// simulates a response that's missing an item
+		if strings.HasSuffix(item.Source.Location, ".drop") {
+			continue
+		}
+		if strings.HasSuffix(item.Source.Location, ".tmp") {
+			msg := apiErrorResponseTemplate{Message: "not allowed"}
+			resp.Items = append(resp.Items, bulkItem[Import]{Error: &msg})
+			continue
+		}
+		job := Import{ID: "import-" + item.Source.Location, State: TransferPending, Source: item.Source}
+		resp.Items = append(resp.Items, bulkItem[Import]{Resource: job})
+	}
+	if err := json.NewEncoder(w).Encode(&resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestStartImports(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleImports)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	reqs := make([]ImportRequest, 0)
+	for i := 0; i < 2*bulkMaxItems+1; i++ {
+		location := "a.bam"
+		if i == bulkMaxItems {
+			location = "b.tmp"
+		}
+		reqs = append(reqs, ImportRequest{Source: VolumeLocation{Volume: "user/bucket", Location: location}})
+	}
+
+	submissions := client.StartImports(reqs)
+	if len(submissions) != len(reqs) {
+		t.Fatalf("expected %d submissions, got %d", len(reqs), len(submissions))
+	}
+	for i, s := range submissions {
+		if i == bulkMaxItems {
+			if s.Err == nil || s.Request.Source.Location != "b.tmp" {
+				t.Fatalf("expected import of 'b.tmp' to fail, got %+v", s)
+			}
+			continue
+		}
+		if s.Err != nil || s.Import.ID != "import-a.bam" {
+			t.Fatalf("expected import of 'a.bam' to start, got %+v", s)
+		}
+	}
+
+	reqs[bulkMaxItems+1].Source.Location = "c.drop"
+	submissions = client.StartImports(reqs)
+	if len(submissions) != len(reqs) {
+		t.Fatalf("expected %d submissions, got %d", len(reqs), len(submissions))
+	}
+	for i, s := range submissions {
+		failed := i >= bulkMaxItems && i < 2*bulkMaxItems
+		if failed && (s.Err == nil || !strings.Contains(s.Err.Error(), "items in the response")) {
+			t.Fatalf("expected submission %d to fail with an error about the missing item, got %+v", i, s)
+		}
+		if !failed && (s.Err != nil || s.Import.ID != "import-a.bam") {
+			t.Fatalf("expected submission %d to start despite the failed chunk, got %+v", i, s)
+		}
+	}
+}
+
+func TestWaitImport(t *testing.T) {
+	setWaitIntervals(t)
+
+	states := []string{TransferPending, TransferRunning, TransferRunning, TransferFailed}
+	polls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		job := Import{ID: "import", State: states[polls]}
+		if job.State == TransferFailed {
+			job.Error = &TransferError{Message: "access denied"}
+		}
+		if polls < len(states)-1 {
+			polls++
+		}
+		if err := json.NewEncoder(w).Encode(&job); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+	client := New("")
+	client.baseURL = ts.URL + "/"
+
+	changes := make([]string, 0)
+	job, err := client.WaitImport(context.Background(), "import", func(i Import) {
+		changes = append(changes, i.State)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if job.State != TransferFailed || job.Error == nil || job.Error.Message != "access denied" {
+		t.Fatalf("expected import to fail with an error, got %+v", job)
+	}
+	if strings.Join(changes, ",") != "PENDING,RUNNING,FAILED" {
+		t.Fatalf("expected 'PENDING,RUNNING,FAILED' changes, got '%s'", strings.Join(changes, ","))
+	}
+}
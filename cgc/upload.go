@@ -0,0 +1,243 @@
+package cgc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// uploadPartSize is the size of a part uploaded in a single request, the last part can be smaller.
+	uploadPartSize = 32 * 1024 * 1024
+	// uploadMaxParts is the maximum number of parts a multipart upload can have.
+	uploadMaxParts = 10000
+)
+
+// UploadDestination is where an uploaded file ends up. Either a Project or a Parent folder should be set, the
+// Name defaults to the name of the local file.
+type UploadDestination struct {
+	Project string `json:"project,omitempty"`
+	Parent  string `json:"parent,omitempty"`
+	Name    string `json:"name"`
+}
+
+type uploadInit struct {
+	UploadDestination
+	Size     int64 `json:"size"`
+	PartSize int64 `json:"part_size"`
+}
+
+type uploadSession struct {
+	UploadID string `json:"upload_id"`
+	PartSize int64  `json:"part_size"`
+}
+
+type uploadPart struct {
+	PartNumber int               `json:"part_number"`
+	URL        string            `json:"url"`
+	Method     string            `json:"method"`
+	Headers    map[string]string `json:"headers"`
+	Report     struct {
+		Headers []string `json:"headers"`
+	} `json:"report"`
+}
+
+type uploadPartReport struct {
+	PartNumber int `json:"part_number"`
+	Response   struct {
+		Headers map[string]string `json:"headers"`
+	} `json:"response"`
+}
+
+// UploadFile uploads the local file at path to the destination using a multipart upload. An existing file with the
+// same name is replaced only if overwrite is set. A part that fails for a reason that may go away by itself is
+// retried, the upload is aborted if any of the parts still fails or the upload can't be completed.
+func (c Client) UploadFile(path string, dest UploadDestination, overwrite bool) (File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return File{}, fmt.Errorf("opening '%s' file failed: %s", path, err.Error())
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return File{}, fmt.Errorf("reading '%s' file info failed: %s", path, err.Error())
+	}
+	if dest.Name == "" {
+		dest.Name = filepath.Base(path)
+	}
+
+	partSize := int64(uploadPartSize)
+	for info.Size() > partSize*uploadMaxParts {
+		partSize *= 2
+	}
+	session, err := c.initUpload(uploadInit{UploadDestination: dest, Size: info.Size(), PartSize: partSize}, overwrite)
+	if err != nil {
+		return File{}, err
+	}
+	if session.PartSize > 0 {
+		partSize = session.PartSize
+	}
+	if info.Size() > partSize*uploadMaxParts {
+		c.abortUpload(session.UploadID)
+		return File{}, fmt.Errorf("part size of %d bytes is too small to upload '%s' file in %d parts", partSize, path,
+			uploadMaxParts)
+	}
+
+	buf := make([]byte, partSize)
+	for number := 1; ; number++ {
+		n, err := io.ReadFull(f, buf)
+		if err == io.EOF && number > 1 {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			c.abortUpload(session.UploadID)
+			return File{}, fmt.Errorf("reading '%s' file failed: %s", path, err.Error())
+		}
+		if err := c.retryUploadPart(session.UploadID, number, buf[:n]); err != nil {
+			c.abortUpload(session.UploadID)
+			return File{}, err
+		}
+		if n < len(buf) {
+			break
+		}
+	}
+
+	file, err := c.completeUpload(session.UploadID)
+	if err != nil {
+		c.abortUpload(session.UploadID)
+		return File{}, err
+	}
+	return file, nil
+}
+
+// initUpload starts a multipart upload session.
+func (c Client) initUpload(init uploadInit, overwrite bool) (uploadSession, error) {
+	encoded, err := json.Marshal(init)
+	if err != nil {
+		return uploadSession{}, fmt.Errorf("encoding upload failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += "upload/multipart"
+	if overwrite {
+		u.RawQuery = "overwrite=true"
+	}
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return uploadSession{}, fmt.Errorf("starting upload failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var session uploadSession
+	if err := json.NewDecoder(resp).Decode(&session); err != nil {
+		return uploadSession{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	return session, nil
+}
+
+// retryUploadPart uploads a single part of the file, making the same backed off attempts WaitTask makes when the
+// part fails for a reason that may go away by itself.
+func (c Client) retryUploadPart(uploadID string, number int, data []byte) error {
+	interval := waitMinInterval
+	for retries := 0; ; retries++ {
+		err := c.uploadPart(uploadID, number, data)
+		if err == nil || !transient(err) || retries == waitMaxRetries {
+			return err
+		}
+		time.Sleep(interval)
+		interval = nextInterval(interval)
+	}
+}
+
+// uploadPart uploads a single part of the file. The API hands out a URL the part is sent to, and expects the
+// response headers it asks for to be reported back once the part is uploaded.
+func (c Client) uploadPart(uploadID string, number int, data []byte) error {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("upload/multipart/%s/part/%d", uploadID, number)
+	// upload links expire, so they can't come from the cache
+	resp, err := c.WithCache(nil).request(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("fetching upload URL of part %d failed: %w", number, err)
+	}
+	defer resp.Close()
+
+	var part uploadPart
+	if err := json.NewDecoder(resp).Decode(&part); err != nil {
+		return fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	if part.Method == "" {
+		part.Method = http.MethodPut
+	}
+
+	// the part URL points to the storage, so the request is made without the API token
+	req, err := http.NewRequest(part.Method, part.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request failed: %s", err.Error())
+	}
+	req.ContentLength = int64(len(data))
+	for key, val := range part.Headers {
+		req.Header.Set(key, val)
+	}
+	partResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading part %d failed: %w", number, err)
+	}
+	defer partResp.Body.Close()
+	if partResp.StatusCode < 200 || partResp.StatusCode > 299 {
+		statusErr := &StatusError{StatusCode: partResp.StatusCode, Message: http.StatusText(partResp.StatusCode)}
+		return fmt.Errorf("uploading part %d failed: %w", number, statusErr)
+	}
+
+	report := uploadPartReport{PartNumber: number}
+	report.Response.Headers = make(map[string]string)
+	for _, header := range part.Report.Headers {
+		report.Response.Headers[header] = partResp.Header.Get(header)
+	}
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("encoding part report failed: %s", err.Error())
+	}
+
+	u = mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("upload/multipart/%s/part", uploadID)
+	reportResp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("reporting part %d failed: %w", number, err)
+	}
+	defer reportResp.Close()
+
+	return nil
+}
+
+// completeUpload finishes the upload session and returns the uploaded file.
+func (c Client) completeUpload(uploadID string) (File, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("upload/multipart/%s/complete", uploadID)
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return File{}, fmt.Errorf("completing upload failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var file File
+	if err := json.NewDecoder(resp).Decode(&file); err != nil {
+		return File{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+	return file, nil
+}
+
+// abortUpload discards the parts uploaded so far. It's only called when the upload already failed, so its own
+// error is ignored.
+func (c Client) abortUpload(uploadID string) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("upload/multipart/%s", uploadID)
+	if resp, err := c.request(http.MethodDelete, u, nil); err == nil {
+		resp.Close()
+	}
+}
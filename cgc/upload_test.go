@@ -0,0 +1,185 @@
+package cgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// uploadServer fakes both the upload endpoints of the API and the storage the parts are sent to.
+type uploadServer struct {
+	url      string
+	partSize int64
+	// failPart fails failures times with the failStatus before it's accepted
+	failPart     int
+	failures     int
+	failStatus   int
+	failComplete bool
+	parts        map[int]string
+	aborted      bool
+}
+
+func (s *uploadServer) handleStorage(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(tokenHeader) != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/storage/"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if number == s.failPart && s.failures > 0 {
+		s.failures--
+		w.WriteHeader(s.failStatus)
+		return
+	}
+	bs, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	s.parts[number] = string(bs)
+	w.Header().Set("ETag", fmt.Sprintf("etag-%d", number))
+}
+
+func (s *uploadServer) handleAPI(w http.ResponseWriter, r *http.Request) {
+	partPat := regexp.MustCompile(`^/upload/multipart/up1/part/(\d+)$`)
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/multipart":
+		var init uploadInit
+		if err := json.NewDecoder(r.Body).Decode(&init); err != nil || init.Name == "" || init.Project == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(uploadSession{UploadID: "up1", PartSize: s.partSize})
+	case r.Method == http.MethodGet && partPat.MatchString(r.URL.Path):
+		number := partPat.FindStringSubmatch(r.URL.Path)[1]
+		part := map[string]interface{}{
+			"url":    s.url + "/storage/" + number,
+			"method": http.MethodPut,
+			"report": map[string][]string{"headers": {"ETag"}},
+		}
+		json.NewEncoder(w).Encode(part)
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/multipart/up1/part":
+		var report uploadPartReport
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if report.Response.Headers["ETag"] != fmt.Sprintf("etag-%d", report.PartNumber) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("{}"))
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/multipart/up1/complete":
+		if s.failComplete {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		content := ""
+		for i := 1; i <= len(s.parts); i++ {
+			content += s.parts[i]
+		}
+		json.NewEncoder(w).Encode(File{ID: "uploaded", Name: content, Size: int64(len(content))})
+	case r.Method == http.MethodDelete && r.URL.Path == "/upload/multipart/up1":
+		s.aborted = true
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	setWaitIntervals(t)
+
+	type in struct {
+		content      string
+		partSize     int64
+		failPart     int
+		failures     int
+		failStatus   int
+		failComplete bool
+	}
+	type out struct {
+		parts   int
+		aborted bool
+		err     error
+	}
+	tooLarge := strings.Repeat("a", uploadMaxParts+1)
+	td := []struct {
+		label string
+		in    in
+		out   out
+	}{
+		{"Single part", in{"hello", 100, 0, 0, 0, false}, out{1, false, nil}},
+		{"Many parts", in{"hello world", 4, 0, 0, 0, false}, out{3, false, nil}},
+		{"Exact parts", in{"abcdefgh", 4, 0, 0, 0, false}, out{2, false, nil}},
+		{"Empty file", in{"", 4, 0, 0, 0, false}, out{1, false, nil}},
+		{"Retried part", in{"hello world", 4, 2, waitMaxRetries, http.StatusServiceUnavailable, false},
+			out{3, false, nil}},
+		{"Failed part", in{"hello world", 4, 2, waitMaxRetries + 1, http.StatusInternalServerError, false},
+			out{1, true, fmt.Errorf("uploading part 2 failed")}},
+		{"Rejected part", in{"hello world", 4, 2, 1, http.StatusForbidden, false},
+			out{1, true, fmt.Errorf("uploading part 2 failed")}},
+		{"Too small part size", in{tooLarge, 1, 0, 0, 0, false}, out{0, true, fmt.Errorf("too small")}},
+		{"Failed completion", in{"hello", 100, 0, 0, 0, true}, out{1, true, fmt.Errorf("completing upload failed")}},
+	}
+
+	testToken := "test_token"
+	contentType := "application/json"
+
+	for _, tt := range td {
+		t.Run(tt.label, func(t *testing.T) {
+			s := &uploadServer{
+				partSize:     tt.in.partSize,
+				failPart:     tt.in.failPart,
+				failures:     tt.in.failures,
+				failStatus:   tt.in.failStatus,
+				failComplete: tt.in.failComplete,
+				parts:        make(map[int]string),
+			}
+			mux := http.NewServeMux()
+			mux.HandleFunc("/storage/", s.handleStorage)
+			mux.HandleFunc("/", tokenMiddleware(testToken, contentTypeMiddleware(contentType, s.handleAPI)))
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+			s.url = ts.URL
+			client := New(testToken)
+			client.baseURL = ts.URL + "/"
+
+			path := filepath.Join(t.TempDir(), "reads.fastq")
+			if err := os.WriteFile(path, []byte(tt.in.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := client.UploadFile(path, UploadDestination{Project: testProjectID}, false)
+			if len(s.parts) != tt.out.parts || s.aborted != tt.out.aborted {
+				t.Fatalf("expected %d parts and aborted %v, got %d and %v", tt.out.parts, tt.out.aborted,
+					len(s.parts), s.aborted)
+			}
+			if err != nil {
+				if tt.out.err != nil {
+					if !strings.Contains(err.Error(), tt.out.err.Error()) {
+						t.Fatalf("expected '%v', got '%v'", tt.out.err, err)
+					}
+					return
+				}
+				t.Fatalf("expected no error, got '%v'", err)
+			}
+			if tt.out.err != nil {
+				t.Fatalf("expected error '%v', got none", tt.out.err)
+			}
+			if file.Name != tt.in.content {
+				t.Fatalf("expected uploaded content '%s', got '%s'", tt.in.content, file.Name)
+			}
+		})
+	}
+}
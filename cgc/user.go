@@ -0,0 +1,44 @@
+package cgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// User struct represents the information about the token holder returned from CGC API.
+type User struct {
+	Href        string   `json:"href"`
+	Username    string   `json:"username"`
+	Email       string   `json:"email"`
+	FirstName   string   `json:"first_name"`
+	LastName    string   `json:"last_name"`
+	Affiliation string   `json:"affiliation"`
+	Division    string   `json:"division"`
+	Role        string   `json:"role"`
+	Tags        []string `json:"tags"`
+}
+
+// User gets the details of the token holder. Since every valid token belongs to a user, this is also the cheapest
+// way to check whether the token is valid.
+func (c Client) User() (User, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += "user"
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return User{}, fmt.Errorf("fetching user details failed: %w", err)
+	}
+	defer resp.Close()
+
+	var user User
+	if err := json.NewDecoder(resp).Decode(&user); err != nil {
+		return User{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return user, nil
+}
+
+// BaseURL returns the URL of the API the client makes requests to.
+func (c Client) BaseURL() string {
+	return c.baseURL
+}
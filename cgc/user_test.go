@@ -0,0 +1,45 @@
+package cgc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func handleUser(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/user" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	user := User{Username: "rfranklin", Email: "rosalind@example.com", Affiliation: "King's College"}
+	if err := json.NewEncoder(w).Encode(&user); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestUser(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleUser)))
+	defer ts.Close()
+
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+	user, err := client.User()
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if user.Username != "rfranklin" || user.Affiliation != "King's College" {
+		t.Fatalf("unexpected user: %+v", user)
+	}
+
+	client = New("expired_token")
+	client.baseURL = ts.URL + "/"
+	_, err = client.User()
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized error, got '%v'", err)
+	}
+}
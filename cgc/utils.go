@@ -15,6 +15,16 @@ func decodeError(r io.Reader) error {
 	return fmt.Errorf("%s", resp.Message)
 }
 
+// contains reports whether the value is one of the values.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func mustParseURL(s string) *url.URL {
 	if u, err := url.Parse(s); err != nil {
 		panic(err)
@@ -22,3 +32,15 @@ func mustParseURL(s string) *url.URL {
 		return u
 	}
 }
+
+// addEscapedPath appends the already escaped path to the path of u. Segments that come from user input (e.g. usernames
+// of division members, which contain a slash) are escaped with url.PathEscape before they're put in the path, so they
+// don't end up split into several segments.
+func addEscapedPath(u *url.URL, escaped string) {
+	raw := u.EscapedPath() + escaped
+	path, err := url.PathUnescape(raw)
+	if err != nil {
+		panic(err)
+	}
+	u.Path, u.RawPath = path, raw
+}
@@ -0,0 +1,234 @@
+package cgc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Types of cloud storage services a volume can connect to.
+const (
+	VolumeS3  = "s3"
+	VolumeGCS = "gcs"
+)
+
+// Access modes of a volume.
+const (
+	VolumeReadOnly  = "RO"
+	VolumeReadWrite = "RW"
+)
+
+// VolumeCredentials struct represents the credentials used to access a bucket. S3 buckets are accessed either with
+// the access keys or by assuming a role, while GCS buckets are accessed with a service account.
+type VolumeCredentials struct {
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	RoleARN         string `json:"role_arn,omitempty"`
+	ExternalID      string `json:"external_id,omitempty"`
+	ClientEmail     string `json:"client_email,omitempty"`
+	PrivateKey      string `json:"private_key,omitempty"`
+}
+
+// VolumeService struct represents the cloud storage service a volume is connected to.
+type VolumeService struct {
+	Type        string            `json:"type"`
+	Bucket      string            `json:"bucket"`
+	Prefix      string            `json:"prefix,omitempty"`
+	Endpoint    string            `json:"endpoint,omitempty"`
+	Credentials VolumeCredentials `json:"credentials"`
+}
+
+// Volume struct represents the volume information returned from CGC API.
+type Volume struct {
+	Href        string        `json:"href"`
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	AccessMode  string        `json:"access_mode"`
+	Active      bool          `json:"active"`
+	Service     VolumeService `json:"service"`
+	CreatedOn   time.Time     `json:"created_on"`
+	ModifiedOn  time.Time     `json:"modified_on"`
+}
+
+// Redacted returns a copy of the volume without the secret parts of its credentials, safe for printing.
+func (v Volume) Redacted() Volume {
+	v.Service.Credentials.SecretAccessKey = ""
+	v.Service.Credentials.PrivateKey = ""
+	return v
+}
+
+// VolumeEntry struct represents an entry of a volume listing returned from CGC API. Entries with Prefix set are
+// the common prefixes of objects (the 'directories' of a bucket), the other ones are objects with Location set.
+type VolumeEntry struct {
+	Href     string                 `json:"href"`
+	Volume   string                 `json:"volume"`
+	Location string                 `json:"location"`
+	Prefix   string                 `json:"prefix"`
+	Type     string                 `json:"type"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// Volumes lists all the volumes available to the token holder.
+func (c Client) Volumes() ([]Volume, error) {
+	volumes, err := collect(c.IterateVolumes(ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching volumes failed: %s", err.Error())
+	}
+	return volumes, nil
+}
+
+// IterateVolumes returns an iterator over the volumes available to the token holder.
+func (c Client) IterateVolumes(opts ListOptions) *Iterator[Volume] {
+	u := mustParseURL(c.baseURL)
+	u.Path += "storage/volumes"
+	return newIterator[Volume](c, u, opts)
+}
+
+// StatVolume gets the details of the volume that has the ID of volumeID.
+func (c Client) StatVolume(volumeID string) (Volume, error) {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("storage/volumes/%s", volumeID)
+	resp, err := c.request(http.MethodGet, u, nil)
+	if err != nil {
+		return Volume{}, fmt.Errorf("fetching volume details failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var volume Volume
+	if err := json.NewDecoder(resp).Decode(&volume); err != nil {
+		return Volume{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return volume, nil
+}
+
+// CreateVolume creates a new volume out of the name, description, access mode and service of the given volume.
+// Other fields are set by the API. Returns the volume as it was created.
+func (c Client) CreateVolume(volume Volume) (Volume, error) {
+	body := struct {
+		Name        string        `json:"name"`
+		Description string        `json:"description,omitempty"`
+		AccessMode  string        `json:"access_mode"`
+		Service     VolumeService `json:"service"`
+	}{volume.Name, volume.Description, volume.AccessMode, volume.Service}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return Volume{}, fmt.Errorf("encoding volume failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += "storage/volumes"
+	resp, err := c.request(http.MethodPost, u, bytes.NewReader(encoded))
+	if err != nil {
+		return Volume{}, fmt.Errorf("creating volume failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var created Volume
+	if err := json.NewDecoder(resp).Decode(&created); err != nil {
+		return Volume{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return created, nil
+}
+
+// UpdateVolume updates the fields of the volume that has the ID of volumeID (e.g. 'description', 'access_mode' or
+// 'service' with new credentials) with the given values. Returns the volume as it is after the update.
+func (c Client) UpdateVolume(volumeID string, fields map[string]interface{}) (Volume, error) {
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return Volume{}, fmt.Errorf("encoding volume update failed: %s", err.Error())
+	}
+
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("storage/volumes/%s", volumeID)
+	resp, err := c.request(http.MethodPatch, u, bytes.NewReader(encoded))
+	if err != nil {
+		return Volume{}, fmt.Errorf("updating volume failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	var volume Volume
+	if err := json.NewDecoder(resp).Decode(&volume); err != nil {
+		return Volume{}, fmt.Errorf("unmarshalling response failed: %s", err.Error())
+	}
+
+	return volume, nil
+}
+
+// DeactivateVolume deactivates the volume that has the ID of volumeID. Volumes have to be deactivated before they
+// can be deleted.
+func (c Client) DeactivateVolume(volumeID string) (Volume, error) {
+	return c.UpdateVolume(volumeID, map[string]interface{}{"active": false})
+}
+
+// DeleteVolume deletes the deactivated volume that has the ID of volumeID.
+func (c Client) DeleteVolume(volumeID string) error {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("storage/volumes/%s", volumeID)
+	resp, err := c.request(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("deleting volume failed: %s", err.Error())
+	}
+	defer resp.Close()
+
+	return nil
+}
+
+// IterateVolume returns an iterator over the contents of the volume with volumeID under the prefix. Objects nested
+// deeper than the prefix are grouped into common prefixes, the same way a file system directory is listed. Common
+// prefixes of a page come before its objects, and both count towards the Limit of opts, the same as the keys of a
+// bucket listing do.
+func (c Client) IterateVolume(volumeID, prefix string, opts ListOptions) *Iterator[VolumeEntry] {
+	u := mustParseURL(c.baseURL)
+	u.Path += fmt.Sprintf("storage/volumes/%s/list", volumeID)
+	params := url.Values{}
+	if prefix != "" {
+		params.Add("prefix", prefix)
+	}
+	u.RawQuery = params.Encode()
+	it := newIterator[VolumeEntry](c, u, opts)
+	it.decode = decodeVolumePage
+	return it
+}
+
+// decodeVolumePage decodes a page of a volume listing, which holds the common prefixes next to the objects.
+func decodeVolumePage(r io.Reader) ([]VolumeEntry, string, error) {
+	var page struct {
+		apiOKResponseTemplate
+		Prefixes []VolumeEntry `json:"prefixes"`
+		Items    []VolumeEntry `json:"items"`
+	}
+	if err := json.NewDecoder(r).Decode(&page); err != nil {
+		return nil, "", err
+	}
+	return append(page.Prefixes, page.Items...), page.next(), nil
+}
+
+// VolumeObjects lists all the objects on the volume with volumeID under the prefix, including the ones nested under
+// deeper prefixes.
+func (c Client) VolumeObjects(volumeID, prefix string) ([]VolumeEntry, error) {
+	entries, err := collect(c.IterateVolume(volumeID, prefix, ListOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching volume contents failed: %s", err.Error())
+	}
+
+	objects := make([]VolumeEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Prefix == "" {
+			objects = append(objects, entry)
+			continue
+		}
+		nested, err := c.VolumeObjects(volumeID, entry.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, nested...)
+	}
+	return objects, nil
+}
@@ -0,0 +1,90 @@
+package cgc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func handleVolumes(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/storage/volumes":
+		var volume Volume
+		if err := json.NewDecoder(r.Body).Decode(&volume); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		volume.ID = "user/" + volume.Name
+		volume.Active = true
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(&volume); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/storage/volumes/user/bucket/list":
+		var resp struct {
+			apiOKResponseTemplate
+			Prefixes []VolumeEntry `json:"prefixes"`
+			Items    []VolumeEntry `json:"items"`
+		}
+		prefix := r.URL.Query().Get("prefix")
+		resp.Prefixes = []VolumeEntry{{Prefix: prefix + "dir/"}}
+		resp.Items = []VolumeEntry{{Location: prefix + "a.bam"}, {Location: prefix + "b.bam"}}
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestCreateVolume(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleVolumes)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	volume, err := client.CreateVolume(Volume{
+		Name:       "bucket",
+		AccessMode: VolumeReadOnly,
+		Service: VolumeService{
+			Type:        VolumeS3,
+			Bucket:      "bucket",
+			Credentials: VolumeCredentials{AccessKeyID: "id", SecretAccessKey: "secret"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if volume.ID != "user/bucket" || !volume.Active {
+		t.Fatalf("unexpected volume created: %+v", volume)
+	}
+	if redacted := volume.Redacted(); redacted.Service.Credentials.SecretAccessKey != "" {
+		t.Fatalf("expected secret access key to be redacted")
+	}
+	if volume.Service.Credentials.SecretAccessKey != "secret" {
+		t.Fatalf("expected redacting to leave the original volume untouched")
+	}
+}
+
+func TestIterateVolume(t *testing.T) {
+	testToken := "test_token"
+	contentType := "application/json"
+	ts := httptest.NewServer(tokenMiddleware(testToken, contentTypeMiddleware(contentType, handleVolumes)))
+	defer ts.Close()
+	client := New(testToken)
+	client.baseURL = ts.URL + "/"
+
+	entries, err := collect(client.IterateVolume("user/bucket", "data/", ListOptions{}))
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Prefix != "data/dir/" || entries[1].Location != "data/a.bam" {
+		t.Fatalf("expected prefixes to come before items, got %+v", entries)
+	}
+}
@@ -0,0 +1,69 @@
+package cgc
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Intervals between polls made while waiting for a task, a transfer or a restore to finish. The interval starts at
+// the minimum and doubles with every poll that shows no progress, up to the maximum. A poll that fails with a
+// transient error is retried at most waitMaxRetries times in a row.
+var (
+	waitMinInterval = 5 * time.Second
+	waitMaxInterval = 2 * time.Minute
+	waitMaxRetries  = 5
+)
+
+// waitUntilDone polls the resource with the ID of id using stat until done reports it's done. The interval between
+// polls starts at waitMinInterval and doubles with every poll that shows no change, up to waitMaxInterval. onChange
+// is called every time changed reports a difference from the previous state. Transient errors (see transient) are
+// retried up to waitMaxRetries times in a row before giving up. stat shouldn't go through the cache, or the changes
+// would only show up once the cached state expires.
+func waitUntilDone[T any](
+	ctx context.Context, id string, stat func(string) (T, error), changed func(before, after T) bool,
+	done func(T) bool, onChange func(T),
+) (T, error) {
+	var last T
+	interval := waitMinInterval
+	for first, retries := true, 0; ; {
+		item, err := stat(id)
+		switch {
+		case err != nil && (!transient(err) || retries >= waitMaxRetries):
+			return last, err
+		case err != nil:
+			retries++
+			interval = nextInterval(interval)
+		case first || changed(last, item):
+			if onChange != nil {
+				onChange(item)
+			}
+			first, retries = false, 0
+			interval = waitMinInterval
+			last = item
+		default:
+			retries = 0
+			interval = nextInterval(interval)
+			last = item
+		}
+
+		if err == nil && done(item) {
+			return item, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("waiting for '%s' stopped: %w", id, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// nextInterval doubles the interval between polls, up to waitMaxInterval.
+func nextInterval(interval time.Duration) time.Duration {
+	interval *= 2
+	if interval > waitMaxInterval {
+		interval = waitMaxInterval
+	}
+	return interval
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var appsCmd = cli.Command{
+	Usage: "A set of commands for listing, inspecting and publishing apps.",
+	Name:  "apps",
+}
+
+var appsListCmd = cli.Command{
+	Name: "list",
+	Usage: fmt.Sprintf(
+		"Lists apps available to the user, the ones in a project provided with '%s' flag or the public ones.",
+		projectFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		filter := cgc.AppFilter{
+			Project: projectID,
+			Public:  c.Bool(publicFlag.Name),
+		}
+		it := client.IterateApps(filter, cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for first := true; it.Next(); first = false {
+			if first {
+				reportTotal(it.Total())
+			}
+			app := it.Item()
+			fmt.Println(app.ID, app.Revision, app.Name)
+		}
+		return it.Err()
+	},
+}
+
+var appsGetCmd = cli.Command{
+	Name: "get",
+	Usage: fmt.Sprintf(
+		"Prints the CWL description of an app provided with '%s' flag, at the latest revision or the one "+
+			"provided with '%s' flag.",
+		appFlag.Name, revisionFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		raw, err := client.RawApp(c.String(appFlag.Name), c.Int(revisionFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(raw)
+	},
+}
+
+var appsRevisionsCmd = cli.Command{
+	Name:  "revisions",
+	Usage: fmt.Sprintf("Lists revisions of an app provided with '%s' flag.", appFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		revisions, err := client.AppRevisions(c.String(appFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		for _, r := range revisions {
+			fmt.Println(r.Revision, r.ModifiedOn.Format(time.RFC3339), r.ModifiedBy, r.Notes)
+		}
+		return nil
+	},
+}
+
+var appsCopyCmd = cli.Command{
+	Name: "copy",
+	Usage: fmt.Sprintf(
+		"Copies an app provided with '%s' flag into a project provided with '%s' flag.",
+		appFlag.Name, projectFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		app, err := client.CopyApp(c.String(appFlag.Name), projectID, c.String(appNameFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(app.ID, app.Revision)
+		return nil
+	},
+}
+
+var appsPushCmd = cli.Command{
+	Name: "push",
+	Usage: fmt.Sprintf(
+		"Creates a new revision of an app provided with '%s' flag out of a CWL file provided with '%s' flag.",
+		appFlag.Name, cwlFlag.Name,
+	),
+	UsageText: fmt.Sprintf(
+		"The CWL file can be either JSON or YAML. The app is created if '%s' flag is set, in which case the app ID "+
+			"is in format 'owner/project/name'.",
+		createFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		var raw map[string]interface{}
+		if err := decodeYAMLFile(c.String(cwlFlag.Name), &raw); err != nil {
+			return err
+		}
+
+		push := client.PushApp
+		if c.Bool(createFlag.Name) {
+			push = client.CreateApp
+		}
+		app, err := push(c.String(appFlag.Name), raw)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(app.ID, app.Revision)
+		return nil
+	},
+}
+
+var publicFlag = cli.BoolFlag{
+	Usage: "list the public apps",
+	Name:  "public",
+}
+var revisionFlag = cli.IntFlag{
+	Usage: "revision of the app, the latest one if not set",
+	Name:  "revision",
+	Value: cgc.LatestRevision,
+}
+var appNameFlag = cli.StringFlag{
+	Usage: "name of the app",
+	Name:  "name",
+}
+var cwlFlag = cli.StringFlag{
+	Usage: "path to a CWL file in JSON or YAML format",
+	Name:  "file",
+}
+var createFlag = cli.BoolFlag{
+	Usage: "create a new app instead of a new revision of an existing one",
+	Name:  "create",
+}
+
+func init() {
+	appsListCmd.Flags = []cli.Flag{projectFlag, publicFlag, limitFlag}
+	appsGetCmd.Flags = []cli.Flag{appFlag, revisionFlag}
+	appsRevisionsCmd.Flags = []cli.Flag{appFlag}
+	appsCopyCmd.Flags = []cli.Flag{appFlag, projectFlag, appNameFlag}
+	appsPushCmd.Flags = []cli.Flag{appFlag, cwlFlag, createFlag}
+
+	appsCmd.Subcommands = []cli.Command{
+		appsListCmd,
+		appsGetCmd,
+		appsCopyCmd,
+		appsPushCmd,
+		appsRevisionsCmd,
+	}
+}
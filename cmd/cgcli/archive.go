@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var filesArchiveCmd = cli.Command{
+	Name:  "archive",
+	Usage: "Moves the selected files to the archive storage, where they can't be used until they're restored.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		ids, err := selectedFileIDs(c, client)
+		if err != nil {
+			return err
+		}
+		prompt := fmt.Sprintf("Archive %d files?", len(ids))
+		if !c.Bool(yesFlag.Name) && !confirm(prompt) {
+			return fmt.Errorf("archiving files cancelled")
+		}
+
+		_, err = reportFileActions(client.ArchiveFiles(ids), "archiving")
+		return err
+	},
+}
+
+var filesRestoreCmd = cli.Command{
+	Name:  "restore",
+	Usage: "Starts restoring the selected files from the archive storage.",
+	UsageText: fmt.Sprintf(
+		"Restoring takes hours. With '%s' flag the command waits for the restores to finish, and with '%s' flag "+
+			"the restored files are then downloaded into that directory. Exits with %d if any restore failed and "+
+			"with %d if the timeout expired.",
+		restoreWaitFlag.Name, destFlag.Name, exitRestoreFailed, exitRestoreTimeout,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		ids, err := selectedFileIDs(c, client)
+		if err != nil {
+			return err
+		}
+		// files that failed to start restoring are reported only after the others are waited for
+		restoring, startErr := reportFileActions(client.RestoreFiles(ids), "restoring")
+		if !c.Bool(restoreWaitFlag.Name) {
+			return startErr
+		}
+
+		restored, err := waitRestores(c, client, restoring)
+		if err != nil {
+			if startErr != nil {
+				fmt.Fprintln(os.Stderr, startErr.Error())
+			}
+			return err
+		}
+
+		dest := c.String(destFlag.Name)
+		if dest == "" {
+			return startErr
+		}
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return fmt.Errorf("creating '%s' directory failed: %s", dest, err.Error())
+		}
+		for _, file := range restored {
+			if err := client.DownloadFile(file.ID, filepath.Join(dest, file.Name)); err != nil {
+				return err
+			}
+			fmt.Println(filepath.Join(dest, file.Name))
+		}
+		return startErr
+	},
+}
+
+var filesRestoreStatusCmd = cli.Command{
+	Name:  "restore-status",
+	Usage: "Prints the storage class and the restore status of the selected files.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		ids, err := selectedFileIDs(c, client)
+		if err != nil {
+			return err
+		}
+		// the restore status changes by itself, so it can't come from the cache
+		fresh := client.WithCache(nil)
+		for _, id := range ids {
+			file, err := fresh.StatFile(id)
+			if err != nil {
+				return err
+			}
+			printRestoreStatus(file)
+		}
+		return nil
+	},
+}
+
+// Exit codes used when waiting for files to be restored.
+const (
+	exitRestoreFailed  = 2
+	exitRestoreTimeout = 4
+)
+
+// reportFileActions prints the IDs of the files the action succeeded on, and the errors for the other files to the
+// standard error. Returns the IDs of the files the action succeeded on, and an error if it failed on any file.
+func reportFileActions(results []cgc.FileActionResult, action string) ([]string, error) {
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s '%s' failed: %s\n", action, r.FileID, r.Err.Error())
+			continue
+		}
+		fmt.Println(r.FileID)
+		ids = append(ids, r.FileID)
+	}
+	if failed := len(results) - len(ids); failed > 0 {
+		return ids, fmt.Errorf("%s %d out of %d files failed", action, failed, len(results))
+	}
+	return ids, nil
+}
+
+// waitRestores waits for the files with the given IDs to be restored, one after another, printing the restore
+// status of each file as it changes. The timeout applies to all the files together. Returns the restored files.
+func waitRestores(c *cli.Context, client cgc.Client, ids []string) ([]cgc.File, error) {
+	ctx := context.Background()
+	if timeout := c.Duration(restoreTimeoutFlag.Name); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	restored := make([]cgc.File, 0, len(ids))
+	for _, id := range ids {
+		file, err := client.WaitRestore(ctx, id, printRestoreStatus)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, cli.NewExitError(err.Error(), exitRestoreTimeout)
+			}
+			return nil, err
+		}
+		if file.Archived() {
+			continue
+		}
+		restored = append(restored, file)
+	}
+	if failed := len(ids) - len(restored); failed > 0 {
+		msg := fmt.Sprintf("restoring %d out of %d files failed", failed, len(ids))
+		return nil, cli.NewExitError(msg, exitRestoreFailed)
+	}
+	return restored, nil
+}
+
+// printRestoreStatus prints a line describing the storage class and the restore status of the file.
+func printRestoreStatus(file cgc.File) {
+	status := file.Storage.RestoreStatus
+	if status == "" {
+		status = "-"
+	}
+	fmt.Println(time.Now().Format(time.RFC3339), file.ID, file.Storage.Class, status, file.Name)
+}
+
+var restoreWaitFlag = cli.BoolFlag{
+	Usage: "wait for the restores to finish, printing their status as it changes",
+	Name:  "wait",
+}
+var restoreTimeoutFlag = cli.DurationFlag{
+	Usage: "stop waiting for the restores after this long (e.g. '12h'), 0 waits forever",
+	Name:  "timeout",
+}
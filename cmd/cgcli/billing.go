@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+// Formats the cost breakdown can be printed in.
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+// dateFormat is the format of the dates accepted by the billing commands.
+const dateFormat = "2006-01-02"
+
+var billingCmd = cli.Command{
+	Usage: "A set of commands for inspecting billing groups and their costs.",
+	Name:  "billing",
+}
+
+var billingGroupsCmd = cli.Command{
+	Usage: "A set of commands for inspecting billing groups.",
+	Name:  "groups",
+}
+
+var billingGroupsListCmd = cli.Command{
+	Name:  "list",
+	Usage: "Lists billing groups the user is a member of, along with their balance.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		it := client.IterateBillingGroups(cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for first := true; it.Next(); first = false {
+			if first {
+				reportTotal(it.Total())
+			}
+			group := it.Item()
+			fmt.Printf("%s %.2f %s %s\n", group.ID, group.Balance.Amount, group.Balance.Currency, group.Name)
+		}
+
+		return it.Err()
+	},
+}
+
+var billingBreakdownCmd = cli.Command{
+	Name: "breakdown",
+	Usage: fmt.Sprintf(
+		"Prints the costs of a billing group provided with '%s' flag, aggregated by project, task or user.",
+		groupFlag.Name,
+	),
+	UsageText: fmt.Sprintf(
+		"The period is given either with '%s' and '%s' flags (dates in YYYY-MM-DD format, both inclusive) or as a "+
+			"whole month with '%s' flag (e.g. '2024-05'). Storage costs are only included when aggregating by project.",
+		fromFlag.Name, toFlag.Name, monthFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		from, to, err := billingPeriod(c)
+		if err != nil {
+			return err
+		}
+		format := c.String(formatFlag.Name)
+		if format != formatCSV && format != formatJSON {
+			return fmt.Errorf("unknown format '%s', use '%s' or '%s'", format, formatCSV, formatJSON)
+		}
+
+		groupID := c.String(groupFlag.Name)
+		analysis, err := client.AnalysisBreakdown(groupID, from, to)
+		if err != nil {
+			return err
+		}
+		var storage []cgc.StorageCost
+		if c.String(costByFlag.Name) == cgc.CostByProject {
+			if storage, err = client.StorageBreakdown(groupID, from, to); err != nil {
+				return err
+			}
+		}
+		summaries, err := cgc.AggregateCosts(analysis, storage, c.String(costByFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		if format == formatJSON {
+			return json.NewEncoder(os.Stdout).Encode(summaries)
+		}
+		return writeCostsCSV(summaries, c.String(costByFlag.Name))
+	},
+}
+
+// billingPeriod parses the period of the breakdown out of the flags. Zero times are returned for the bounds that
+// aren't set.
+func billingPeriod(c *cli.Context) (time.Time, time.Time, error) {
+	if month := c.String(monthFlag.Name); month != "" {
+		if c.IsSet(fromFlag.Name) || c.IsSet(toFlag.Name) {
+			return time.Time{}, time.Time{}, fmt.Errorf(
+				"'%s' flag can't be used together with '%s' and '%s' flags", monthFlag.Name, fromFlag.Name, toFlag.Name,
+			)
+		}
+		from, err := time.Parse("2006-01", month)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("malformed month '%s', expected YYYY-MM", month)
+		}
+		return from, from.AddDate(0, 1, -1), nil
+	}
+
+	var bounds [2]time.Time
+	for i, name := range []string{fromFlag.Name, toFlag.Name} {
+		if s := c.String(name); s != "" {
+			t, err := time.Parse(dateFormat, s)
+			if err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("malformed '%s' date '%s', expected YYYY-MM-DD", name, s)
+			}
+			bounds[i] = t
+		}
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// writeCostsCSV writes the cost summaries to the standard output in CSV format, with a header naming the columns.
+func writeCostsCSV(summaries []cgc.CostSummary, by string) error {
+	w := csv.NewWriter(os.Stdout)
+	header := []string{by}
+	if by == cgc.CostByTask {
+		header = append(header, "name")
+	}
+	header = append(header, "analysis_cost", "storage_cost", "total_cost", "currency", "tasks")
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("writing CSV failed: %s", err.Error())
+	}
+
+	amount := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	for _, s := range summaries {
+		record := []string{s.Key}
+		if by == cgc.CostByTask {
+			record = append(record, s.Name)
+		}
+		record = append(record, amount(s.Analysis), amount(s.Storage), amount(s.Total), s.Currency, strconv.Itoa(s.Tasks))
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("writing CSV failed: %s", err.Error())
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("writing CSV failed: %s", err.Error())
+	}
+	return nil
+}
+
+var groupFlag = cli.StringFlag{
+	Usage: "represents the billing group ID",
+	Name:  "group",
+}
+var fromFlag = cli.StringFlag{
+	Usage: "start of the period in YYYY-MM-DD format",
+	Name:  "from",
+}
+var toFlag = cli.StringFlag{
+	Usage: "end of the period in YYYY-MM-DD format",
+	Name:  "to",
+}
+var monthFlag = cli.StringFlag{
+	Usage: "the whole month in YYYY-MM format, instead of the start and the end of the period",
+	Name:  "month",
+}
+var costByFlag = cli.StringFlag{
+	Usage: fmt.Sprintf("aggregate costs by '%s', '%s' or '%s'", cgc.CostByProject, cgc.CostByTask, cgc.CostByUser),
+	Name:  "by",
+	Value: cgc.CostByProject,
+}
+var formatFlag = cli.StringFlag{
+	Usage: fmt.Sprintf("output format, '%s' or '%s'", formatCSV, formatJSON),
+	Name:  "format",
+	Value: formatCSV,
+}
+
+func init() {
+	billingGroupsListCmd.Flags = []cli.Flag{limitFlag}
+	billingBreakdownCmd.Flags = []cli.Flag{groupFlag, fromFlag, toFlag, monthFlag, costByFlag, formatFlag}
+
+	billingGroupsCmd.Subcommands = []cli.Command{
+		billingGroupsListCmd,
+	}
+	billingCmd.Subcommands = []cli.Command{
+		billingGroupsCmd,
+		billingBreakdownCmd,
+	}
+}
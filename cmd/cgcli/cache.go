@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var cacheCmd = cli.Command{
+	Usage: "A set of commands for managing the responses of the API cached on the disk.",
+	Name:  "cache",
+}
+
+var cacheClearCmd = cli.Command{
+	Name:  "clear",
+	Usage: "Removes every cached response, for every token.",
+	Action: func(c *cli.Context) error {
+		cache, err := responseCache(c)
+		if err != nil {
+			return err
+		}
+		return cache.Clear()
+	},
+}
+
+var cacheStatsCmd = cli.Command{
+	Name:  "stats",
+	Usage: "Prints the number and the size of the cached responses.",
+	Action: func(c *cli.Context) error {
+		cache, err := responseCache(c)
+		if err != nil {
+			return err
+		}
+		stats, err := cache.Stats()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("entries:     %d\n", stats.Entries)
+		fmt.Printf("size:        %s\n", formatSize(stats.Size))
+		fmt.Printf("revalidated: %d\n", stats.Validated)
+		fmt.Printf("expired:     %d\n", stats.Expired)
+		return nil
+	},
+}
+
+// responseCache returns the cache the responses of the API are kept in, next to the cached completions.
+func responseCache(c *cli.Context) (*cgc.Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("finding cache directory failed: %s", err.Error())
+	}
+	return cgc.NewCache(filepath.Join(dir, "cgcli", "responses"), c.GlobalDuration(cacheTTLFlag.Name)), nil
+}
+
+func init() {
+	cacheCmd.Subcommands = []cli.Command{
+		cacheClearCmd,
+		cacheStatsCmd,
+	}
+}
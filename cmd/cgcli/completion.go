@@ -0,0 +1,261 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+// completionCacheTTL is how long the projects and files fetched for completion are reused before they're fetched
+// again. It's kept short, since completing stale IDs is worse than completing slowly.
+const completionCacheTTL = 5 * time.Minute
+
+// fishCompleteEnv is set by the fish completion script, so the completions are printed with descriptions in the
+// format fish expects.
+const fishCompleteEnv = "_CGCLI_FISH_COMPLETE"
+
+const bashCompletion = `_cgcli_complete() {
+  local cur opts
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  if [[ "$cur" == "-"* ]]; then
+    opts=$( "${COMP_WORDS[@]:0:$COMP_CWORD}" "$cur" --generate-bash-completion 2>/dev/null )
+  else
+    opts=$( "${COMP_WORDS[@]:0:$COMP_CWORD}" --generate-bash-completion 2>/dev/null )
+  fi
+  COMPREPLY=( $(compgen -W "${opts}" -- "${cur}") )
+  return 0
+}
+
+complete -o bashdefault -o default -F _cgcli_complete cgcli
+`
+
+const zshCompletion = `#compdef cgcli
+
+_cgcli_complete() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(_CLI_ZSH_AUTOCOMPLETE_HACK=1 ${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion 2>/dev/null)}")
+  else
+    opts=("${(@f)$(_CLI_ZSH_AUTOCOMPLETE_HACK=1 ${words[@]:0:#words[@]-1} --generate-bash-completion 2>/dev/null)}")
+  fi
+
+  if [[ "${opts[1]}" != "" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+
+compdef _cgcli_complete cgcli
+`
+
+const fishCompletion = `function __cgcli_complete
+  set -l args (commandline -opc)
+  set -e args[1]
+  set -l cur (commandline -ct)
+  if string match -q -- '-*' $cur
+    ` + fishCompleteEnv + `=1 command cgcli $args $cur --generate-bash-completion 2>/dev/null
+  else
+    ` + fishCompleteEnv + `=1 command cgcli $args --generate-bash-completion 2>/dev/null
+  end
+end
+
+complete -c cgcli -f -a '(__cgcli_complete)'
+`
+
+var completionCmd = cli.Command{
+	Name:      "completion",
+	Usage:     "Prints the shell completion script for bash, zsh or fish.",
+	ArgsUsage: "bash|zsh|fish",
+	UsageText: "Load the script in the shell's startup file, e.g. 'source <(cgcli completion bash)' for bash, " +
+		"'source <(cgcli completion zsh)' for zsh or 'cgcli completion fish | source' for fish. Values of the " +
+		"project and file flags are completed with the projects and files of the user, as long as the token is " +
+		"given before the command.",
+	Action: func(c *cli.Context) error {
+		scripts := map[string]string{
+			"bash": bashCompletion,
+			"zsh":  zshCompletion,
+			"fish": fishCompletion,
+		}
+		script, ok := scripts[c.Args().First()]
+		if !ok {
+			return fmt.Errorf("unknown shell '%s', use 'bash', 'zsh' or 'fish'", c.Args().First())
+		}
+		fmt.Print(script)
+		return nil
+	},
+}
+
+// completionItem is a single value a flag can be completed with.
+type completionItem struct {
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+// enableCompletion sets up completion of the flag values for every command that has no subcommands. Commands with
+// subcommands complete the names of the subcommands.
+func enableCompletion(commands []cli.Command) {
+	for i := range commands {
+		if len(commands[i].Subcommands) > 0 {
+			enableCompletion(commands[i].Subcommands)
+			continue
+		}
+		commands[i].BashComplete = completeCommand(&commands[i])
+	}
+}
+
+// completeCommand returns the completion function for the command. If the word before the one being completed is
+// the project or the file flag, projects or files of the user are completed, else the flags of the command are.
+func completeCommand(cmd *cli.Command) cli.BashCompleteFunc {
+	return func(c *cli.Context) {
+		// the last argument is the flag asking for completion, the one before it is the previous word
+		args := os.Args
+		if len(args) < 3 {
+			return
+		}
+		previous := args[len(args)-2]
+
+		var items []completionItem
+		var err error
+		switch previous {
+		case "--" + projectFlag.Name:
+			items, err = completeProjects(c)
+		case "--" + fileFlag.Name:
+			items, err = completeFiles(c, flagValue(args, "--"+projectFlag.Name))
+		default:
+			cli.DefaultCompleteWithFlags(cmd)(c)
+			return
+		}
+		// completion should never print errors into the shell, it completes nothing instead
+		if err != nil {
+			return
+		}
+		printCompletions(items)
+	}
+}
+
+// completeProjects returns the projects of the user, fetching them only if the cached ones are too old.
+func completeProjects(c *cli.Context) ([]completionItem, error) {
+	token := c.GlobalString(tokenFlag.Name)
+	if token == "" {
+		return nil, fmt.Errorf("no token")
+	}
+
+	return cachedCompletions(token, "projects", func() ([]completionItem, error) {
+		projects, err := cgc.New(token).Projects()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]completionItem, 0, len(projects))
+		for _, p := range projects {
+			items = append(items, completionItem{p.ID, p.Name})
+		}
+		return items, nil
+	})
+}
+
+// completeFiles returns the files of the project given by ref, fetching them only if the cached ones are too old.
+// Nothing is completed if the project isn't known yet.
+func completeFiles(c *cli.Context, ref string) ([]completionItem, error) {
+	token := c.GlobalString(tokenFlag.Name)
+	if token == "" || ref == "" {
+		return nil, fmt.Errorf("no token or project")
+	}
+
+	return cachedCompletions(token, "files "+ref, func() ([]completionItem, error) {
+		client := cgc.New(token)
+		projectID, err := client.ResolveProject(ref)
+		if err != nil {
+			return nil, err
+		}
+		files, err := client.Files(projectID)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]completionItem, 0, len(files))
+		for _, f := range files {
+			items = append(items, completionItem{f.ID, f.Name})
+		}
+		return items, nil
+	})
+}
+
+// cachedCompletions returns the completions cached under the key for the token, or fetches and caches them if they
+// are missing or older than the TTL. Failing to cache is not an error, the completions are just fetched every time.
+func cachedCompletions(token, key string, fetch func() ([]completionItem, error)) ([]completionItem, error) {
+	path, pathErr := completionCachePath(token, key)
+	if pathErr == nil {
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < completionCacheTTL {
+			var items []completionItem
+			if bs, err := os.ReadFile(path); err == nil && json.Unmarshal(bs, &items) == nil {
+				return items, nil
+			}
+		}
+	}
+
+	items, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if pathErr == nil {
+		if bs, err := json.Marshal(items); err == nil && os.MkdirAll(filepath.Dir(path), 0700) == nil {
+			_ = os.WriteFile(path, bs, 0600)
+		}
+	}
+	return items, nil
+}
+
+// completionCachePath returns the path of the file the completions under the key are cached in for the token. The
+// token is hashed together with the key, so nothing is read from the cache of another user and the token itself
+// never ends up on the disk.
+func completionCachePath(token, key string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(token + "\x00" + key))
+	return filepath.Join(dir, "cgcli", "completion", hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// printCompletions prints the completions in the format the shell asking for them expects. Bash can't show
+// descriptions, so only the values are printed for it.
+func printCompletions(items []completionItem) {
+	for _, item := range items {
+		// values with whitespace would be split into several completions
+		if strings.ContainsAny(item.Value, " \t\n") {
+			continue
+		}
+		switch {
+		case os.Getenv("_CLI_ZSH_AUTOCOMPLETE_HACK") == "1":
+			fmt.Printf("%s:%s\n", strings.ReplaceAll(item.Value, ":", "\\:"), item.Description)
+		case os.Getenv(fishCompleteEnv) == "1":
+			fmt.Printf("%s\t%s\n", item.Value, item.Description)
+		default:
+			fmt.Println(item.Value)
+		}
+	}
+}
+
+// flagValue returns the value following the flag in the arguments, or an empty string if the flag isn't there.
+func flagValue(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(arg, flag+"=") {
+			return strings.TrimPrefix(arg, flag+"=")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var datasetsCmd = cli.Command{
+	Usage: "A set of commands for querying hosted datasets (e.g. TCGA or TARGET).",
+	Name:  "datasets",
+}
+
+var datasetsListCmd = cli.Command{
+	Name:  "list",
+	Usage: "Lists datasets available to the user.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		datasets, err := client.Datasets()
+		if err != nil {
+			return err
+		}
+		for _, dataset := range datasets {
+			fmt.Println(dataset.ID, dataset.Description)
+		}
+		return nil
+	},
+}
+
+var datasetsSchemaCmd = cli.Command{
+	Name: "schema",
+	Usage: fmt.Sprintf(
+		"Prints a JSON string describing the properties of an entity provided with '%s' flag of a dataset "+
+			"provided with '%s' flag.",
+		entityFlag.Name, datasetFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		schema, err := client.DatasetSchema(c.String(datasetFlag.Name), c.String(entityFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(schema)
+	},
+}
+
+var datasetsQueryCmd = cli.Command{
+	Name: "query",
+	Usage: fmt.Sprintf(
+		"Prints the IDs of entities of a dataset provided with '%s' flag matching a query.",
+		datasetFlag.Name,
+	),
+	UsageText: fmt.Sprintf(
+		"The query is either read from a JSON or YAML file provided with '%s' flag, or built out of '%s' and '%s' "+
+			"flags (e.g. '--%s files --%s hasDataFormat=BAM --%s hasCase.hasGender=Female'). IDs of file entities "+
+			"are the IDs of the files, which can be copied into a project.",
+		queryFileFlag.Name, entityFlag.Name, datasetFilterFlag.Name,
+		entityFlag.Name, datasetFilterFlag.Name, datasetFilterFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		query, err := datasetQuery(c)
+		if err != nil {
+			return err
+		}
+
+		if c.Bool(countFlag.Name) {
+			total, err := client.CountDataset(c.String(datasetFlag.Name), query)
+			if err != nil {
+				return err
+			}
+			fmt.Println(total)
+			return nil
+		}
+
+		it := client.IterateDataset(c.String(datasetFlag.Name), query, cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for it.Next() {
+			fmt.Println(it.Item().ID)
+		}
+		return it.Err()
+	},
+}
+
+var datasetsCopyCmd = cli.Command{
+	Name: "copy",
+	Usage: fmt.Sprintf(
+		"Copies the files of a dataset provided with '%s' flag matching a query into a project provided with '%s' flag.",
+		datasetFlag.Name, projectFlag.Name,
+	),
+	UsageText: fmt.Sprintf(
+		"The query is given the same way as for the 'query' command and has to be over '%s'. Files with the same "+
+			"name as a file already in the project are skipped. With '%s' flag only the files that would be copied "+
+			"are reported.",
+		cgc.DatasetFiles, dryRunFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		query, err := datasetQuery(c)
+		if err != nil {
+			return err
+		}
+		if query.Entity() != cgc.DatasetFiles {
+			return fmt.Errorf("only '%s' can be copied, the query is over '%s'", cgc.DatasetFiles, query.Entity())
+		}
+
+		entities, err := client.QueryDataset(c.String(datasetFlag.Name), query)
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(entities))
+		for _, entity := range entities {
+			ids = append(ids, entity.ID)
+		}
+		results := client.StatFiles(ids)
+		// files that can't be read can't be copied either, the rest are copied anyway
+		files := make([]cgc.File, 0, len(results))
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Fprintf(os.Stderr, "fetching '%s' failed: %s\n", r.FileID, r.Err.Error())
+				continue
+			}
+			files = append(files, r.File)
+		}
+		unreadable := len(results) - len(files)
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		missing, present, err := client.SkipExisting(files, projectID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%d files match the query, %d are already in the project\n", len(results), len(present))
+
+		if c.Bool(dryRunFlag.Name) {
+			var size int64
+			for _, file := range missing {
+				fmt.Println(file.ID, file.Name)
+				size += file.Size
+			}
+			fmt.Fprintf(os.Stderr, "would copy %d files, %s in total\n", len(missing), formatSize(size))
+			if unreadable > 0 {
+				return fmt.Errorf("fetching %d out of %d files failed", unreadable, len(results))
+			}
+			return nil
+		}
+
+		sizes := make(map[string]int64, len(missing))
+		toCopy := make([]string, 0, len(missing))
+		for _, file := range missing {
+			sizes[file.ID] = file.Size
+			toCopy = append(toCopy, file.ID)
+		}
+		copies := client.CopyFiles(toCopy, projectID)
+
+		var copied int
+		var size int64
+		for _, r := range copies {
+			if r.Err != nil {
+				fmt.Fprintf(os.Stderr, "copying '%s' failed: %s\n", r.FileID, r.Err.Error())
+				continue
+			}
+			fmt.Println(r.NewFileID, r.NewFileName)
+			copied++
+			size += sizes[r.FileID]
+		}
+		fmt.Fprintf(os.Stderr, "copied %d files, %s in total\n", copied, formatSize(size))
+		if failed := len(copies) - copied + unreadable; failed > 0 {
+			return fmt.Errorf("copying %d out of %d files failed", failed, len(copies)+unreadable)
+		}
+		return nil
+	},
+}
+
+// formatSize formats the number of bytes in the largest binary unit that keeps the number above one.
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTP"[exp])
+}
+
+// datasetQuery reads the query from the file provided with the query flag, or builds it out of the entity and the
+// filter flags.
+func datasetQuery(c *cli.Context) (cgc.DatasetQuery, error) {
+	if path := c.String(queryFileFlag.Name); path != "" {
+		query := cgc.DatasetQuery{}
+		if err := decodeYAMLFile(path, &query); err != nil {
+			return nil, err
+		}
+		if query.Entity() == "" {
+			query["entity"] = c.String(entityFlag.Name)
+		}
+		return query, nil
+	}
+
+	filters := make(map[string]string)
+	for _, filter := range c.StringSlice(datasetFilterFlag.Name) {
+		kv := strings.SplitN(filter, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed '%s' flag: %s", datasetFilterFlag.Name, filter)
+		}
+		filters[kv[0]] = kv[1]
+	}
+	return cgc.NewDatasetQuery(c.String(entityFlag.Name), filters), nil
+}
+
+var datasetFlag = cli.StringFlag{
+	Usage: "represents the dataset ID (e.g. 'tcga')",
+	Name:  "dataset",
+}
+var entityFlag = cli.StringFlag{
+	Usage: fmt.Sprintf(
+		"entity of the dataset, e.g. '%s', '%s' or '%s'",
+		cgc.DatasetFiles, cgc.DatasetCases, cgc.DatasetSamples,
+	),
+	Name:  "entity",
+	Value: cgc.DatasetFiles,
+}
+var queryFileFlag = cli.StringFlag{
+	Usage: "path to a file holding the query in JSON or YAML format",
+	Name:  "query",
+}
+var datasetFilterFlag = cli.StringSliceFlag{
+	Usage: "filter in format 'key=value', where the key can be a dotted path to a related entity, can be repeated",
+	Name:  "filter",
+}
+var countFlag = cli.BoolFlag{
+	Usage: "only print the number of matching entities",
+	Name:  "count",
+}
+
+func init() {
+	datasetsSchemaCmd.Flags = []cli.Flag{datasetFlag, entityFlag}
+	datasetsQueryCmd.Flags = []cli.Flag{datasetFlag, queryFileFlag, entityFlag, datasetFilterFlag, countFlag, limitFlag}
+	datasetsCopyCmd.Flags = []cli.Flag{datasetFlag, queryFileFlag, entityFlag, datasetFilterFlag, projectFlag, dryRunFlag}
+
+	datasetsCmd.Subcommands = []cli.Command{
+		datasetsListCmd,
+		datasetsSchemaCmd,
+		datasetsQueryCmd,
+		datasetsCopyCmd,
+	}
+}
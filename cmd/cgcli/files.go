@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/doza-daniel/cgcli/cgc"
 	"github.com/urfave/cli"
@@ -17,19 +19,78 @@ var filesCmd = cli.Command{
 var filesListCmd = cli.Command{
 	Name:  "list",
 	Usage: fmt.Sprintf("List files that belong under a project provided with '%s' flag.", projectFlag.Name),
+	UsageText: fmt.Sprintf(
+		"Files can be narrowed down with the filtering flags. With '%s' flag the files are listed from a snapshot "+
+			"taken with 'projects snapshot' command, without access to the API.",
+		snapshotFlag.Name,
+	),
 	Action: func(c *cli.Context) error {
-		token := c.GlobalString(tokenFlag.Name)
-		projectID := c.String(projectFlag.Name)
-
-		client := cgc.New(token)
-		files, err := client.Files(projectID)
+		filter, err := fileFilter(c)
 		if err != nil {
 			return err
 		}
 
-		for _, file := range files {
+		if path := c.String(snapshotFlag.Name); path != "" {
+			snapshot, err := cgc.LoadSnapshot(path)
+			if err != nil {
+				return err
+			}
+			entries := snapshot.List(filter)
+			if limit := c.Int(limitFlag.Name); limit > 0 && limit < len(entries) {
+				entries = entries[:limit]
+			}
+			for _, entry := range entries {
+				fmt.Println(entry.Name, entry.ID)
+			}
+			return nil
+		}
+
+		client := newClient(c)
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		it := client.IterateFiles(projectID, filter, cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for first := true; it.Next(); first = false {
+			if first {
+				reportTotal(it.Total())
+			}
+			file := it.Item()
 			fmt.Println(file.Name, file.ID)
 		}
+		return it.Err()
+	},
+}
+
+var filesFindCmd = cli.Command{
+	Name: "find",
+	Usage: fmt.Sprintf(
+		"Finds files anywhere in a project snapshot provided with '%s' flag, printing their paths and IDs.",
+		snapshotFlag.Name,
+	),
+	UsageText: "Files are selected with the same filtering flags that are used with the API. The snapshot is " +
+		"taken with 'projects snapshot' command, and searching it doesn't need access to the API.",
+	Action: func(c *cli.Context) error {
+		path := c.String(snapshotFlag.Name)
+		if path == "" {
+			return fmt.Errorf("'%s' flag is required", snapshotFlag.Name)
+		}
+		filter, err := fileFilter(c)
+		if err != nil {
+			return err
+		}
+		snapshot, err := cgc.LoadSnapshot(path)
+		if err != nil {
+			return err
+		}
+
+		entries := snapshot.Find(filter)
+		if limit := c.Int(limitFlag.Name); limit > 0 && limit < len(entries) {
+			entries = entries[:limit]
+		}
+		for _, entry := range entries {
+			fmt.Println(entry.Path, entry.ID)
+		}
 		return nil
 	},
 }
@@ -40,14 +101,74 @@ var filesUpdateCmd = cli.Command{
 	UsageText: "Takes the arguments in format 'metadata.key=value' or 'key=value' and updates those fields in a file.",
 	Action: func(c *cli.Context) error {
 
-		token := c.GlobalString(tokenFlag.Name)
-		fileID := c.String(fileFlag.Name)
+		problems, err := cgc.ValidateUpdates(c.Args())
+		if err != nil {
+			return err
+		}
+		for _, p := range problems {
+			if p.Unknown {
+				fmt.Fprintln(os.Stderr, "warning:", p)
+			}
+		}
+		if err := cgc.MetadataError(problems, c.Bool(strictFlag.Name)); err != nil {
+			return err
+		}
+
+		client := newClient(c)
+		fileID, err := resolveFile(c, client)
+		if err != nil {
+			return err
+		}
+		err = client.UpdateFile(fileID, c.Args())
+		if err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+var filesMetadataCmd = cli.Command{
+	Name:  "metadata",
+	Usage: "A set of commands for working with file metadata.",
+}
+
+var filesMetadataValidateCmd = cli.Command{
+	Name: "validate",
+	Usage: fmt.Sprintf(
+		"Validates metadata of every file under a project provided with '%s' flag against the standard metadata fields.",
+		projectFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		strict := c.Bool(strictFlag.Name)
 
-		client := cgc.New(token)
-		err := client.UpdateFile(fileID, c.Args())
+		client := newClient(c)
+		projectID, err := resolveProject(c, client)
 		if err != nil {
 			return err
 		}
+		it := client.IterateFiles(projectID, cgc.FileFilter{}, cgc.ListOptions{})
+
+		invalid, count := 0, 0
+		for ; it.Next(); count++ {
+			file, err := client.StatFile(it.Item().ID)
+			if err != nil {
+				return err
+			}
+			problems := cgc.ValidateMetadata(file.Metadata)
+			for _, p := range problems {
+				fmt.Println(file.Name, file.ID, p)
+			}
+			if cgc.MetadataError(problems, strict) != nil {
+				invalid++
+			}
+		}
+
+		if err := it.Err(); err != nil {
+			return err
+		}
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d files have invalid metadata", invalid, count)
+		}
 		return nil
 	},
 }
@@ -59,10 +180,11 @@ var filesStatCmd = cli.Command{
 		fileFlag.Name,
 	),
 	Action: func(c *cli.Context) error {
-		token := c.GlobalString(tokenFlag.Name)
-		fileID := c.String(fileFlag.Name)
-
-		client := cgc.New(token)
+		client := newClient(c)
+		fileID, err := resolveFile(c, client)
+		if err != nil {
+			return err
+		}
 		file, err := client.StatFile(fileID)
 		if err != nil {
 			return err
@@ -82,38 +204,185 @@ var filesDownloadCmd = cli.Command{
 		destFlag.Name,
 	),
 	Action: func(c *cli.Context) error {
-		token := c.GlobalString(tokenFlag.Name)
 		dest := c.String(destFlag.Name)
-		fileID := c.String(fileFlag.Name)
 
-		client := cgc.New(token)
+		client := newClient(c)
+		fileID, err := resolveFile(c, client)
+		if err != nil {
+			return err
+		}
 		return client.DownloadFile(fileID, dest)
 	},
 }
 
 var projectFlag = cli.StringFlag{
-	Usage: "represents the project ID",
+	Usage: "represents the project, by ID ('owner/project'), slug or name",
 	Name:  "project",
 }
 var fileFlag = cli.StringFlag{
-	Usage: "represents the file ID",
+	Usage: "represents the file, by ID or path ('owner/project/path/to/name')",
 	Name:  "file",
 }
 var destFlag = cli.StringFlag{
 	Usage: "a path on a local system",
 	Name:  "dest",
 }
+var fileListFlag = cli.StringSliceFlag{
+	Usage: "represents the file, by ID or path ('owner/project/path/to/name'), can be repeated",
+	Name:  "file",
+}
+var stdinFlag = cli.BoolFlag{
+	Usage: "read file IDs or paths from the standard input, one per line",
+	Name:  "stdin",
+}
+var nameFlag = cli.StringFlag{
+	Usage: "select only the files with this exact name",
+	Name:  "name",
+}
+var withTagFlag = cli.StringSliceFlag{
+	Usage: "select only the files that have this tag, can be repeated to match any of the tags",
+	Name:  "with-tag",
+}
+var whereFlag = cli.StringSliceFlag{
+	Usage: "select only the files with metadata in format 'key=value', can be repeated",
+	Name:  "where",
+}
+var snapshotFlag = cli.StringFlag{
+	Usage: "path to a project snapshot to read the files from instead of the API",
+	Name:  "from-snapshot",
+}
+var strictFlag = cli.BoolFlag{
+	Usage: "treat metadata keys that are not standard metadata fields as errors",
+	Name:  "strict",
+}
+
+// fileSelectionFlags are the flags used by commands that work on many files at once.
+var fileSelectionFlags = []cli.Flag{fileListFlag, stdinFlag, projectFlag, nameFlag, withTagFlag, whereFlag}
+
+// fileFilter builds a file filter out of the filtering flags.
+func fileFilter(c *cli.Context) (cgc.FileFilter, error) {
+	filter := cgc.FileFilter{
+		Name:     c.String(nameFlag.Name),
+		Tags:     c.StringSlice(withTagFlag.Name),
+		Metadata: make(map[string]string),
+	}
+	for _, where := range c.StringSlice(whereFlag.Name) {
+		kv := strings.SplitN(where, "=", 2)
+		if len(kv) != 2 {
+			return cgc.FileFilter{}, fmt.Errorf("malformed '%s' flag: %s", whereFlag.Name, where)
+		}
+		filter.Metadata[strings.TrimPrefix(kv[0], "metadata.")] = kv[1]
+	}
+	return filter, nil
+}
+
+// selectedFileIDs collects the IDs of the files a command should work on. Files can be provided by ID or path with
+// the repeatable '--file' flag, read from the standard input, or selected from a project provided with the
+// '--project' flag, optionally narrowed down with the filtering flags. Folders of the project are never selected.
+func selectedFileIDs(c *cli.Context, client cgc.Client) ([]string, error) {
+	refs := append([]string{}, c.StringSlice(fileListFlag.Name)...)
+
+	if c.Bool(stdinFlag.Name) {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if ref := strings.TrimSpace(scanner.Text()); ref != "" {
+				refs = append(refs, ref)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading file IDs from standard input failed: %s", err.Error())
+		}
+	}
+
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		id, err := client.ResolveFile(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	projectID, err := resolveProject(c, client)
+	if err != nil {
+		return nil, err
+	}
+	if projectID != "" {
+		filter, err := fileFilter(c)
+		if err != nil {
+			return nil, err
+		}
+		files, err := client.FilterFiles(projectID, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			// the commands work on the contents of files, which folders don't have
+			if file.Type == cgc.FileTypeFolder {
+				continue
+			}
+			ids = append(ids, file.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf(
+			"no files selected, use '%s', '%s' or '%s' flags",
+			fileListFlag.Name, stdinFlag.Name, projectFlag.Name,
+		)
+	}
+	return ids, nil
+}
+
+// resolveProject returns the ID of the project provided with the project flag, which can be given by ID, slug or
+// name. Returns an empty string if the flag is not set.
+func resolveProject(c *cli.Context, client cgc.Client) (string, error) {
+	ref := c.String(projectFlag.Name)
+	if ref == "" {
+		return "", nil
+	}
+	return client.ResolveProject(ref)
+}
+
+// resolveFile returns the ID of the file provided with the file flag, which can be given by ID or path.
+func resolveFile(c *cli.Context, client cgc.Client) (string, error) {
+	return client.ResolveFile(c.String(fileFlag.Name))
+}
 
 func init() {
-	filesListCmd.Flags = []cli.Flag{projectFlag}
+	filesListCmd.Flags = []cli.Flag{projectFlag, snapshotFlag, nameFlag, withTagFlag, whereFlag, limitFlag}
+	filesFindCmd.Flags = []cli.Flag{snapshotFlag, nameFlag, withTagFlag, whereFlag, limitFlag}
 	filesStatCmd.Flags = []cli.Flag{fileFlag}
-	filesUpdateCmd.Flags = []cli.Flag{fileFlag}
+	filesUpdateCmd.Flags = []cli.Flag{fileFlag, strictFlag}
 	filesDownloadCmd.Flags = []cli.Flag{fileFlag, destFlag}
+	filesMetadataValidateCmd.Flags = []cli.Flag{projectFlag, strictFlag}
+
+	filesTagAddCmd.Flags = fileSelectionFlags
+	filesTagRemoveCmd.Flags = fileSelectionFlags
+	filesTagListCmd.Flags = fileSelectionFlags
+	filesArchiveCmd.Flags = append([]cli.Flag{yesFlag}, fileSelectionFlags...)
+	filesRestoreCmd.Flags = append([]cli.Flag{restoreWaitFlag, restoreTimeoutFlag, destFlag}, fileSelectionFlags...)
+	filesRestoreStatusCmd.Flags = fileSelectionFlags
+
+	filesMetadataCmd.Subcommands = []cli.Command{
+		filesMetadataValidateCmd,
+	}
+	filesTagCmd.Subcommands = []cli.Command{
+		filesTagAddCmd,
+		filesTagRemoveCmd,
+		filesTagListCmd,
+	}
 
 	filesCmd.Subcommands = []cli.Command{
 		filesListCmd,
+		filesFindCmd,
 		filesUpdateCmd,
 		filesStatCmd,
 		filesDownloadCmd,
+		filesMetadataCmd,
+		filesTagCmd,
+		filesArchiveCmd,
+		filesRestoreCmd,
+		filesRestoreStatusCmd,
 	}
 }
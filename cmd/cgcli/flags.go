@@ -1,5 +1,30 @@
 package main
 
-import "github.com/urfave/cli"
+import (
+	"time"
+
+	"github.com/urfave/cli"
+)
 
 var tokenFlag = cli.StringFlag{Name: "token"}
+
+var noCacheFlag = cli.BoolFlag{
+	Usage: "don't use the responses cached on the disk, nor cache new ones",
+	Name:  "no-cache",
+}
+
+var cacheTTLFlag = cli.DurationFlag{
+	Usage: "how long the cached responses are used for when the API can't tell whether they changed",
+	Name:  "cache-ttl",
+	Value: 5 * time.Minute,
+}
+
+var limitFlag = cli.IntFlag{
+	Usage: "stop after listing this many items, 0 lists everything",
+	Name:  "limit",
+}
+
+var yesFlag = cli.BoolFlag{
+	Usage: "don't ask for confirmation",
+	Name:  "yes",
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+// followInterval is the time between polls of the execution details when following the logs of a running task.
+const followInterval = 30 * time.Second
+
+var tasksLogsCmd = cli.Command{
+	Name:  "logs",
+	Usage: fmt.Sprintf("Prints logs of the jobs of a task provided with '%s' flag.", taskFlag.Name),
+	UsageText: fmt.Sprintf(
+		"Logs of every job are printed, unless a job is provided with '%s' flag. Only the logs whose names contain "+
+			"the text provided with '%s' flag (e.g. 'err') are printed if it's set. When following, execution "+
+			"details are polled until the task finishes and logs of every job are printed once it finishes.",
+		jobFlag.Name, logFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+		taskID := c.String(taskFlag.Name)
+
+		follow := c.Bool(followFlag.Name)
+		if follow {
+			// the task is polled for changes, so it can't come from the cache
+			client = client.WithCache(nil)
+		}
+		printed := make(map[string]bool)
+		for {
+			task, err := client.StatTask(taskID)
+			if err != nil {
+				return err
+			}
+			details, err := client.TaskExecutionDetails(taskID)
+			if err != nil {
+				return err
+			}
+
+			for _, job := range details.Jobs {
+				if name := c.String(jobFlag.Name); name != "" && job.Name != name {
+					continue
+				}
+				// logs of unfinished jobs are incomplete, so they are printed once the job finishes
+				if follow && !task.Done() && (job.Status == cgc.TaskQueued || job.Status == cgc.TaskRunning) {
+					continue
+				}
+
+				logs := make([]string, 0, len(job.Logs))
+				for log := range job.Logs {
+					if strings.Contains(log, c.String(logFlag.Name)) && !printed[job.Name+"/"+log] {
+						logs = append(logs, log)
+					}
+				}
+				sort.Strings(logs)
+
+				for _, log := range logs {
+					fmt.Printf("==> %s (%s on %s) %s <==\n", job.Name, job.Status, job.Instance.Type, log)
+					if err := printFile(client, job.Logs[log]); err != nil {
+						return err
+					}
+					printed[job.Name+"/"+log] = true
+				}
+			}
+
+			if !follow || task.Done() {
+				return nil
+			}
+			time.Sleep(followInterval)
+		}
+	},
+}
+
+var jobFlag = cli.StringFlag{
+	Usage: "name of the job",
+	Name:  "job",
+}
+var logFlag = cli.StringFlag{
+	Usage: "only print the logs whose names contain this text",
+	Name:  "log",
+}
+var followFlag = cli.BoolFlag{
+	Usage: "keep printing logs of new jobs until the task finishes",
+	Name:  "follow",
+}
+
+// printFile writes the contents of the file with fileID to the standard output.
+func printFile(client cgc.Client, fileID string) error {
+	file, err := client.OpenFile(fileID)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(os.Stdout, file); err != nil {
+		return fmt.Errorf("printing file '%s' failed: %s", fileID, err.Error())
+	}
+	return nil
+}
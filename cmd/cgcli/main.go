@@ -1,10 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strings"
 
+	"github.com/doza-daniel/cgcli/cgc"
 	"github.com/urfave/cli"
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
@@ -13,11 +17,73 @@ func main() {
 	app.Usage = "CLI tool for accessing CGC Public API."
 	app.Version = "1.0.0"
 
-	app.Flags = []cli.Flag{tokenFlag}
-	app.Commands = []cli.Command{projectsCmd, filesCmd}
+	app.Flags = []cli.Flag{tokenFlag, noCacheFlag, cacheTTLFlag}
+	app.Commands = []cli.Command{
+		projectsCmd,
+		filesCmd,
+		tasksCmd,
+		appsCmd,
+		volumesCmd,
+		importsCmd,
+		exportsCmd,
+		billingCmd,
+		datasetsCmd,
+		whoamiCmd,
+		shellCmd,
+		cacheCmd,
+		completionCmd,
+	}
+	app.EnableBashCompletion = true
+	enableCompletion(app.Commands)
 
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// newClient returns a client for the token provided with the token flag. Responses of the API are cached on the disk
+// unless '--no-cache' flag is set.
+func newClient(c *cli.Context) cgc.Client {
+	client := cgc.New(c.GlobalString(tokenFlag.Name))
+	if c.GlobalBool(noCacheFlag.Name) {
+		return client
+	}
+	cache, err := responseCache(c)
+	if err != nil {
+		// there's no place for the cache, which only makes things slower
+		return client
+	}
+	return client.WithCache(cache)
+}
+
+// reportTotal prints the approximate number of items a list command is going to go through to the standard error,
+// so it doesn't get mixed with the listed items. Nothing is printed if the API didn't report the total.
+func reportTotal(total int, ok bool) {
+	if ok {
+		fmt.Fprintf(os.Stderr, "about %d items in total\n", total)
+	}
+}
+
+// confirm asks the user a yes or no question on the standard error and reads the answer from the standard input.
+// Anything but an explicit yes is taken as a no.
+func confirm(prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// decodeYAMLFile decodes the JSON or YAML file at path into v. YAML is a superset of JSON, so both kinds of files can
+// be decoded the same way.
+func decodeYAMLFile(path string, v interface{}) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading '%s' file failed: %s", path, err.Error())
+	}
+	if err := yaml.Unmarshal(bs, v); err != nil {
+		return fmt.Errorf("unmarshalling '%s' file failed: %s", path, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var projectsMembersCmd = cli.Command{
+	Name:  "members",
+	Usage: "A set of commands for managing project members and their permissions.",
+}
+
+var projectsMembersListCmd = cli.Command{
+	Name:  "list",
+	Usage: fmt.Sprintf("Lists members of a project provided with '%s' flag and their permissions.", projectFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		it := client.IterateProjectMembers(projectID, cgc.ListOptions{})
+		for it.Next() {
+			member := it.Item()
+			fmt.Println(member.Username, member.Permissions)
+		}
+		return it.Err()
+	},
+}
+
+var projectsMembersAddCmd = cli.Command{
+	Name: "add",
+	Usage: fmt.Sprintf(
+		"Adds a user provided with '%s' flag to a project, granting them permissions provided with '%s' flag.",
+		usernameFlag.Name, permissionsFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		permissions, err := cgc.ParsePermissions(c.String(permissionsFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		member, err := client.AddMember(projectID, c.String(usernameFlag.Name), permissions)
+		if err != nil {
+			return err
+		}
+		fmt.Println(member.Username, member.Permissions)
+		return nil
+	},
+}
+
+var projectsMembersUpdateCmd = cli.Command{
+	Name: "update",
+	Usage: fmt.Sprintf(
+		"Replaces permissions of a project member provided with '%s' flag with the ones provided with '%s' flag.",
+		usernameFlag.Name, permissionsFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		permissions, err := cgc.ParsePermissions(c.String(permissionsFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		username := c.String(usernameFlag.Name)
+		updated, err := client.UpdateMemberPermissions(projectID, username, permissions)
+		if err != nil {
+			return err
+		}
+		fmt.Println(username, updated)
+		return nil
+	},
+}
+
+var projectsMembersRemoveCmd = cli.Command{
+	Name:  "remove",
+	Usage: fmt.Sprintf("Removes a member provided with '%s' flag from a project.", usernameFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		return client.RemoveMember(projectID, c.String(usernameFlag.Name))
+	},
+}
+
+var projectsMembersApplyCmd = cli.Command{
+	Name: "apply",
+	Usage: fmt.Sprintf(
+		"Applies a roster file provided with '%s' flag to every project provided with '%s' flag and reports the drift.",
+		rosterFlag.Name, projectListFlag.Name,
+	),
+	UsageText: "The roster is a JSON array of members, e.g. " +
+		`'[{"username": "division/alice", "permissions": {"read": true, "copy": true}}]'. ` +
+		"Members missing from a project are added and ones with different permissions are updated. Members that " +
+		"are not on the roster are only removed when pruning.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		f, err := os.Open(c.String(rosterFlag.Name))
+		if err != nil {
+			return fmt.Errorf("opening roster failed: %s", err.Error())
+		}
+		defer f.Close()
+
+		var roster []cgc.Member
+		if err := json.NewDecoder(f).Decode(&roster); err != nil {
+			return fmt.Errorf("unmarshalling roster failed: %s", err.Error())
+		}
+
+		dryRun := c.Bool(dryRunFlag.Name)
+		prune := c.Bool(pruneFlag.Name)
+
+		// the token holder is kept unless asked otherwise, removing them would lock them out of the project
+		var self string
+		if prune && !c.Bool(pruneSelfFlag.Name) {
+			user, err := client.User()
+			if err != nil {
+				return err
+			}
+			self = user.Username
+		}
+		drifted := 0
+		for _, ref := range c.StringSlice(projectListFlag.Name) {
+			projectID, err := client.ResolveProject(ref)
+			if err != nil {
+				return err
+			}
+			current, err := client.ProjectMembers(projectID)
+			if err != nil {
+				return err
+			}
+
+			diff := cgc.DiffRoster(current, roster)
+			if diff.Empty() {
+				fmt.Println(projectID, "in sync")
+				continue
+			}
+			drifted++
+
+			for _, m := range diff.Add {
+				fmt.Println(projectID, "+", m.Username, m.Permissions)
+				if !dryRun {
+					if _, err := client.AddMember(projectID, m.Username, m.Permissions); err != nil {
+						return err
+					}
+				}
+			}
+			for _, m := range diff.Update {
+				fmt.Println(projectID, "~", m.Username, m.Permissions)
+				if !dryRun {
+					if _, err := client.UpdateMemberPermissions(projectID, m.Username, m.Permissions); err != nil {
+						return err
+					}
+				}
+			}
+			for _, m := range diff.Remove {
+				if prune && m.Username == self {
+					fmt.Fprintf(os.Stderr, "%s: not removing '%s', the token holder, without '%s' flag\n",
+						projectID, m.Username, pruneSelfFlag.Name)
+					continue
+				}
+				fmt.Println(projectID, "-", m.Username, m.Permissions)
+				if !dryRun && prune {
+					if err := client.RemoveMember(projectID, m.Username); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		fmt.Printf("%d projects drifted from the roster\n", drifted)
+		return nil
+	},
+}
+
+var usernameFlag = cli.StringFlag{
+	Usage: "represents the username of a project member",
+	Name:  "user",
+}
+var permissionsFlag = cli.StringFlag{
+	Usage: "permissions separated by commas, any of 'read,copy,write,execute,admin'",
+	Name:  "permissions",
+	Value: "read",
+}
+var projectListFlag = cli.StringSliceFlag{
+	Usage: "represents the project, by ID ('owner/project'), slug or name, can be repeated",
+	Name:  "project",
+}
+var rosterFlag = cli.StringFlag{
+	Usage: "path to a roster file",
+	Name:  "roster",
+}
+var dryRunFlag = cli.BoolFlag{
+	Usage: "only report what would be changed",
+	Name:  "dry-run",
+}
+var pruneFlag = cli.BoolFlag{
+	Usage: "remove the project members that are not on the roster",
+	Name:  "prune",
+}
+var pruneSelfFlag = cli.BoolFlag{
+	Usage: "let '--prune' remove the token holder as well",
+	Name:  "prune-self",
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/doza-daniel/cgcli/cgc"
 	"github.com/urfave/cli"
@@ -11,27 +13,199 @@ var projectsListCmd = cli.Command{
 	Name:  "list",
 	Usage: "Lists projects that belong to the user.",
 	Action: func(c *cli.Context) error {
-		client := cgc.New(c.GlobalString(tokenFlag.Name))
+		client := newClient(c)
 
-		projects, err := client.Projects()
+		it := client.IterateProjects(cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for first := true; it.Next(); first = false {
+			if first {
+				reportTotal(it.Total())
+			}
+			fmt.Println(it.Item().ID)
+		}
+
+		return it.Err()
+	},
+}
+
+var projectsStatCmd = cli.Command{
+	Name: "stat",
+	Usage: fmt.Sprintf(
+		"Prints a JSON string representing information about a project provided with '%s' flag.",
+		projectFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		project, err := client.StatProject(projectID)
 		if err != nil {
 			return err
 		}
 
-		for _, p := range projects {
-			fmt.Println(p.ID)
+		return json.NewEncoder(os.Stdout).Encode(project)
+	},
+}
+
+var projectsCreateCmd = cli.Command{
+	Name:  "create",
+	Usage: "Creates a new project and prints a JSON string representing it.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		project, err := client.CreateProject(cgc.Project{
+			Name:         c.String(projectNameFlag.Name),
+			BillingGroup: c.String(billingGroupFlag.Name),
+			Description:  c.String(descriptionFlag.Name),
+		})
+		if err != nil {
+			return err
 		}
 
+		return json.NewEncoder(os.Stdout).Encode(project)
+	},
+}
+
+var projectsUpdateCmd = cli.Command{
+	Name:  "update",
+	Usage: fmt.Sprintf("Update project that's provided with '%s' flag.", projectFlag.Name),
+	UsageText: "Takes the arguments in format 'key=value' or 'settings.key=value' and updates those fields in a project " +
+		"(e.g. 'name=foo', 'description=bar', 'settings.locked=true', 'settings.use_interruptible_instances=false').",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		project, err := client.UpdateProject(projectID, c.Args())
+		if err != nil {
+			return err
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(project)
+	},
+}
+
+var projectsDeleteCmd = cli.Command{
+	Name:  "delete",
+	Usage: fmt.Sprintf("Deletes a project provided with '%s' flag, along with all of its files.", projectFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		project, err := client.StatProject(projectID)
+		if err != nil {
+			return err
+		}
+
+		prompt := fmt.Sprintf("Delete project '%s' (%s) and all of its files?", project.Name, project.ID)
+		if !c.Bool(yesFlag.Name) && !confirm(prompt) {
+			return fmt.Errorf("deleting project '%s' cancelled", project.ID)
+		}
+
+		return client.DeleteProject(project.ID)
+	},
+}
+
+var projectsSnapshotCmd = cli.Command{
+	Name: "snapshot",
+	Usage: fmt.Sprintf(
+		"Saves the details of every file and folder of a project provided with '%s' flag to a file provided with "+
+			"'%s' flag.",
+		projectFlag.Name, outFlag.Name,
+	),
+	UsageText: "The snapshot can be searched later without access to the API, with 'files list --from-snapshot' and " +
+		"'files find' commands.",
+	Action: func(c *cli.Context) error {
+		out := c.String(outFlag.Name)
+		if out == "" {
+			return fmt.Errorf("'%s' flag is required", outFlag.Name)
+		}
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		snapshot, err := client.SnapshotProject(projectID)
+		if err != nil {
+			return err
+		}
+		if err := snapshot.Save(out); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "%d files and folders saved to %s\n", len(snapshot.Entries), out)
+		var failed int
+		for _, entry := range snapshot.Entries {
+			if entry.Error != "" {
+				fmt.Fprintf(os.Stderr, "fetching details of '%s' failed: %s\n", entry.Path, entry.Error)
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d files were saved without their details", failed)
+		}
 		return nil
 	},
 }
+
 var projectsCmd = cli.Command{
 	Usage: "A set of commands for manipulating projects.",
 	Name:  "projects",
 }
 
+var projectNameFlag = cli.StringFlag{
+	Usage: "name of the project",
+	Name:  "name",
+}
+var billingGroupFlag = cli.StringFlag{
+	Usage: "represents the billing group ID",
+	Name:  "billing-group",
+}
+var outFlag = cli.StringFlag{
+	Usage: "path of the file the snapshot is saved to",
+	Name:  "out",
+}
+var descriptionFlag = cli.StringFlag{
+	Usage: "a short description",
+	Name:  "description",
+}
+
 func init() {
+	projectsListCmd.Flags = []cli.Flag{limitFlag}
+	projectsStatCmd.Flags = []cli.Flag{projectFlag}
+	projectsCreateCmd.Flags = []cli.Flag{projectNameFlag, billingGroupFlag, descriptionFlag}
+	projectsUpdateCmd.Flags = []cli.Flag{projectFlag}
+	projectsDeleteCmd.Flags = []cli.Flag{projectFlag, yesFlag}
+	projectsSnapshotCmd.Flags = []cli.Flag{projectFlag, outFlag}
+	projectsMembersListCmd.Flags = []cli.Flag{projectFlag}
+	projectsMembersAddCmd.Flags = []cli.Flag{projectFlag, usernameFlag, permissionsFlag}
+	projectsMembersUpdateCmd.Flags = []cli.Flag{projectFlag, usernameFlag, permissionsFlag}
+	projectsMembersRemoveCmd.Flags = []cli.Flag{projectFlag, usernameFlag}
+	projectsMembersApplyCmd.Flags = []cli.Flag{projectListFlag, rosterFlag, dryRunFlag, pruneFlag, pruneSelfFlag}
+
+	projectsMembersCmd.Subcommands = []cli.Command{
+		projectsMembersListCmd,
+		projectsMembersAddCmd,
+		projectsMembersUpdateCmd,
+		projectsMembersRemoveCmd,
+		projectsMembersApplyCmd,
+	}
+
 	projectsCmd.Subcommands = []cli.Command{
 		projectsListCmd,
+		projectsStatCmd,
+		projectsCreateCmd,
+		projectsUpdateCmd,
+		projectsDeleteCmd,
+		projectsSnapshotCmd,
+		projectsMembersCmd,
 	}
 }
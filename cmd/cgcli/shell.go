@@ -0,0 +1,659 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/peterh/liner"
+	"github.com/urfave/cli"
+)
+
+var shellCmd = cli.Command{
+	Name:  "shell",
+	Usage: "Starts an interactive shell that treats the projects as a remote filesystem.",
+	UsageText: "The root of the filesystem holds the projects, by ID ('owner/project'), slug or name, and the " +
+		"folders of a project are nested under it. Type 'help' in the shell for the list of commands.",
+	Action: func(c *cli.Context) error {
+		sh := &shell{
+			client:   newClient(c),
+			listings: make(map[string][]cgc.File),
+		}
+		return sh.run()
+	},
+}
+
+// shellCommand is a command that can be run in the shell.
+type shellCommand struct {
+	usage string
+	help  string
+	run   func(sh *shell, args []string) error
+}
+
+// shellCommands are the commands of the shell by name. It's filled in init, because the help command refers to it.
+var shellCommands map[string]shellCommand
+
+// errExit is returned by the exit command to end the shell.
+var errExit = errors.New("exit")
+
+// shell keeps the state of an interactive session: the client, the current directory and the listings of the
+// directories visited so far, which are used for resolving paths and completing names.
+type shell struct {
+	client cgc.Client
+	line   *liner.State
+
+	// project is the ID of the current project, it's empty at the root
+	project string
+	// folders are the folders from the root of the project to the current directory
+	folders []cgc.File
+
+	projects []cgc.Project
+	listings map[string][]cgc.File
+}
+
+// run reads and runs the commands until the input ends or the exit command is run.
+func (sh *shell) run() error {
+	sh.line = liner.NewLiner()
+	defer sh.line.Close()
+	sh.line.SetCtrlCAborts(true)
+	sh.line.SetTabCompletionStyle(liner.TabPrints)
+	sh.line.SetWordCompleter(sh.completeWord)
+
+	history := shellHistoryPath()
+	if f, err := os.Open(history); err == nil {
+		sh.line.ReadHistory(f)
+		f.Close()
+	}
+	defer sh.saveHistory(history)
+
+	for {
+		input, err := sh.line.Prompt(fmt.Sprintf("cgc:%s> ", sh.pwd()))
+		if err == liner.ErrPromptAborted {
+			continue
+		}
+		if err == io.EOF {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading input failed: %s", err.Error())
+		}
+
+		args, err := splitShellArgs(input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		sh.line.AppendHistory(input)
+
+		cmd, ok := shellCommands[args[0]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "error: unknown command '%s', type 'help' for the list of commands\n", args[0])
+			continue
+		}
+		if err := cmd.run(sh, args[1:]); err == errExit {
+			return nil
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+	}
+}
+
+// saveHistory writes the history of the session to the history file. Failing to do so is not worth failing the
+// whole session, so the error is only reported.
+func (sh *shell) saveHistory(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		fmt.Fprintln(os.Stderr, "saving history failed:", err)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "saving history failed:", err)
+		return
+	}
+	defer f.Close()
+	if _, err := sh.line.WriteHistory(f); err != nil {
+		fmt.Fprintln(os.Stderr, "saving history failed:", err)
+	}
+}
+
+// shellHistoryPath returns the path of the file the shell history is kept in, or an empty string if there's no
+// place for it.
+func shellHistoryPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cgcli", "shell_history")
+}
+
+// pwd returns the path of the current directory.
+func (sh *shell) pwd() string {
+	return shellPath(sh.project, sh.folders)
+}
+
+// shellPath returns the path of the directory given by the project and the folders in it.
+func shellPath(project string, folders []cgc.File) string {
+	if project == "" {
+		return "/"
+	}
+	path := "/" + project
+	for _, folder := range folders {
+		path += "/" + folder.Name
+	}
+	return path
+}
+
+// resolve walks the path from the current directory, or from the root if the path is absolute, and returns the
+// project and the entries in it the path leads to. Only the last entry can be a file.
+func (sh *shell) resolve(path string) (string, []cgc.File, error) {
+	project, entries := sh.project, append([]cgc.File{}, sh.folders...)
+	if strings.HasPrefix(path, "/") {
+		project, entries = "", nil
+	}
+
+	names := strings.Split(path, "/")
+	for i := 0; i < len(names); i++ {
+		name := names[i]
+		switch {
+		case name == "" || name == ".":
+			continue
+		case name == "..":
+			if len(entries) > 0 {
+				entries = entries[:len(entries)-1]
+			} else {
+				project = ""
+			}
+			continue
+		case len(entries) > 0 && entries[len(entries)-1].Type != cgc.FileTypeFolder:
+			return "", nil, fmt.Errorf("'%s' is not a folder", shellPath(project, entries))
+		}
+
+		if project == "" {
+			// project IDs contain a slash, so they span two names of the path
+			next := ""
+			if i+1 < len(names) {
+				next = names[i+1]
+			}
+			id, consumed, err := sh.resolveProject(name, next)
+			if err != nil {
+				return "", nil, err
+			}
+			project = id
+			if consumed {
+				i++
+			}
+			continue
+		}
+
+		listing, err := sh.list(project, entries)
+		if err != nil {
+			return "", nil, err
+		}
+		matches := make([]cgc.File, 0, 1)
+		for _, entry := range listing {
+			if entry.Name == name {
+				matches = append(matches, entry)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return "", nil, fmt.Errorf("no such file or folder '%s'", shellPath(project, entries)+"/"+name)
+		case 1:
+			entries = append(entries, matches[0])
+		default:
+			return "", nil, fmt.Errorf("'%s' is ambiguous, it matches %d files", name, len(matches))
+		}
+	}
+
+	return project, entries, nil
+}
+
+// resolveProject finds the project referenced by name, which is either the owner part of a project ID, in which case
+// next is the rest of it, or a slug or a name of a project. Reports whether next was used.
+func (sh *shell) resolveProject(name, next string) (string, bool, error) {
+	// a project ID takes up two segments of the path, so they're joined if they make up one of the projects
+	if next != "" {
+		projects, err := sh.listProjects()
+		if err != nil {
+			return "", false, err
+		}
+		for _, project := range projects {
+			if project.ID == name+"/"+next {
+				return project.ID, true, nil
+			}
+		}
+	}
+
+	projectID, err := sh.client.ResolveProject(name)
+	return projectID, false, err
+}
+
+// listProjects returns the projects of the user. They are fetched once per session.
+func (sh *shell) listProjects() ([]cgc.Project, error) {
+	if sh.projects == nil {
+		projects, err := sh.client.Projects()
+		if err != nil {
+			return nil, err
+		}
+		sh.projects = projects
+	}
+	return sh.projects, nil
+}
+
+// list returns the entries of the directory given by the project and the folders in it. Listings are kept for the
+// rest of the session, so resolving paths and completing names doesn't hit the API every time.
+func (sh *shell) list(project string, folders []cgc.File) ([]cgc.File, error) {
+	if listing, ok := sh.listings[listingKey(project, folders)]; ok {
+		return listing, nil
+	}
+	return sh.fetchListing(sh.client, project, folders)
+}
+
+// refresh fetches the listing of the directory again, bypassing the responses cached on the disk as well, so it
+// catches up with the changes made outside of the shell.
+func (sh *shell) refresh(project string, folders []cgc.File) ([]cgc.File, error) {
+	return sh.fetchListing(sh.client.WithCache(nil), project, folders)
+}
+
+// fetchListing fetches the listing of the directory with the client and keeps it for the rest of the session.
+func (sh *shell) fetchListing(client cgc.Client, project string, folders []cgc.File) ([]cgc.File, error) {
+	var it *cgc.Iterator[cgc.File]
+	if len(folders) == 0 {
+		it = client.IterateFiles(project, cgc.FileFilter{}, cgc.ListOptions{})
+	} else {
+		it = client.IterateFolder(folders[len(folders)-1].ID, cgc.ListOptions{})
+	}
+	listing := make([]cgc.File, 0)
+	for it.Next() {
+		listing = append(listing, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	sh.listings[listingKey(project, folders)] = listing
+	return listing, nil
+}
+
+// forget drops the listing of the directory, so it's fetched again the next time it's needed.
+func (sh *shell) forget(project string, folders []cgc.File) {
+	delete(sh.listings, listingKey(project, folders))
+}
+
+// listingKey returns the key the listing of the directory is kept under.
+func listingKey(project string, folders []cgc.File) string {
+	if len(folders) == 0 {
+		return project
+	}
+	return folders[len(folders)-1].ID
+}
+
+// resolveFile resolves the path and checks it leads to a file.
+func (sh *shell) resolveFile(path string) (string, []cgc.File, error) {
+	project, entries, err := sh.resolve(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(entries) == 0 || entries[len(entries)-1].Type == cgc.FileTypeFolder {
+		return "", nil, fmt.Errorf("'%s' is not a file", shellPath(project, entries))
+	}
+	return project, entries, nil
+}
+
+func shellCd(sh *shell, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+	path := "/"
+	if len(args) == 1 {
+		path = args[0]
+	}
+	project, entries, err := sh.resolve(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 && entries[len(entries)-1].Type != cgc.FileTypeFolder {
+		return fmt.Errorf("'%s' is not a folder", shellPath(project, entries))
+	}
+	sh.project, sh.folders = project, entries
+	return nil
+}
+
+func shellPwd(sh *shell, args []string) error {
+	fmt.Println(sh.pwd())
+	return nil
+}
+
+func shellLs(sh *shell, args []string) error {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	for i, path := range args {
+		project, entries, err := sh.resolve(path)
+		if err != nil {
+			return err
+		}
+		if len(args) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s:\n", shellPath(project, entries))
+		}
+
+		if project == "" {
+			// the projects could have changed since the last time, also outside of the shell
+			projects, err := sh.client.WithCache(nil).Projects()
+			if err != nil {
+				return err
+			}
+			sh.projects = projects
+			for _, p := range projects {
+				fmt.Printf("%s/\t%s\n", p.ID, p.Name)
+			}
+			continue
+		}
+		if len(entries) > 0 && entries[len(entries)-1].Type != cgc.FileTypeFolder {
+			printShellEntry(entries[len(entries)-1])
+			continue
+		}
+
+		// listing the directory is a good time to catch up with the changes made outside of the shell
+		listing, err := sh.refresh(project, entries)
+		if err != nil {
+			return err
+		}
+		for _, entry := range listing {
+			printShellEntry(entry)
+		}
+	}
+	return nil
+}
+
+// printShellEntry prints a line of a directory listing. Folders end with a slash, like in the paths.
+func printShellEntry(entry cgc.File) {
+	if entry.Type == cgc.FileTypeFolder {
+		fmt.Printf("%s/\n", entry.Name)
+		return
+	}
+	fmt.Printf("%s\t%s\t%s\n", entry.Name, formatSize(entry.Size), entry.ID)
+}
+
+func shellStat(sh *shell, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing path")
+	}
+	for _, path := range args {
+		project, entries, err := sh.resolve(path)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("'%s' is not a file or a folder", shellPath(project, entries))
+		}
+		file, err := sh.client.StatFile(entries[len(entries)-1].ID)
+		if err != nil {
+			return err
+		}
+		if err := json.NewEncoder(os.Stdout).Encode(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func shellGet(sh *shell, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("expected a remote path and an optional local one")
+	}
+	_, entries, err := sh.resolveFile(args[0])
+	if err != nil {
+		return err
+	}
+	file := entries[len(entries)-1]
+
+	dest := file.Name
+	if len(args) == 2 {
+		dest = args[1]
+		if info, err := os.Stat(dest); err == nil && info.IsDir() {
+			dest = filepath.Join(dest, file.Name)
+		}
+	}
+	if err := sh.client.DownloadFile(file.ID, dest); err != nil {
+		return err
+	}
+	fmt.Printf("%s -> %s\n", file.Name, dest)
+	return nil
+}
+
+func shellPut(sh *shell, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("expected a local path and an optional remote name")
+	}
+	if sh.project == "" {
+		return fmt.Errorf("files can only be uploaded into a project, 'cd' into one first")
+	}
+
+	dest := cgc.UploadDestination{Project: sh.project}
+	if len(sh.folders) > 0 {
+		dest = cgc.UploadDestination{Parent: sh.folders[len(sh.folders)-1].ID}
+	}
+	if len(args) == 2 {
+		dest.Name = args[1]
+	}
+	file, err := sh.client.UploadFile(args[0], dest, false)
+	if err != nil {
+		return err
+	}
+	sh.forget(sh.project, sh.folders)
+	fmt.Printf("%s -> %s/%s\n", args[0], sh.pwd(), file.Name)
+	return nil
+}
+
+func shellRm(sh *shell, args []string) error {
+	force := len(args) > 0 && args[0] == "-f"
+	if force {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("missing path")
+	}
+
+	for _, path := range args {
+		project, entries, err := sh.resolveFile(path)
+		if err != nil {
+			return err
+		}
+		file := entries[len(entries)-1]
+		if !force {
+			answer, err := sh.line.Prompt(fmt.Sprintf("delete '%s'? [y/N] ", shellPath(project, entries)))
+			if err != nil || (strings.ToLower(strings.TrimSpace(answer)) != "y" &&
+				strings.ToLower(strings.TrimSpace(answer)) != "yes") {
+				continue
+			}
+		}
+		if err := sh.client.DeleteFile(file.ID); err != nil {
+			return err
+		}
+		sh.forget(project, entries[:len(entries)-1])
+	}
+	return nil
+}
+
+func shellHelp(sh *shell, args []string) error {
+	for _, name := range shellCommandNames() {
+		cmd := shellCommands[name]
+		fmt.Printf("  %-24s %s\n", cmd.usage, cmd.help)
+	}
+	return nil
+}
+
+func shellExit(sh *shell, args []string) error {
+	return errExit
+}
+
+// shellCommandNames returns the names of the shell commands in alphabetical order.
+func shellCommandNames() []string {
+	names := make([]string, 0, len(shellCommands))
+	for name := range shellCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeWord completes the word under the cursor. The first word is completed with the names of the commands, the
+// rest with the paths of the files and folders, using the listings seen so far where possible.
+func (sh *shell) completeWord(line string, pos int) (string, []string, string) {
+	head, tail := line[:pos], line[pos:]
+	start := len(head)
+	for start > 0 && (head[start-1] != ' ' || (start > 1 && head[start-2] == '\\')) {
+		start--
+	}
+	word := strings.ReplaceAll(head[start:], "\\ ", " ")
+	head = head[:start]
+
+	completions := make([]string, 0)
+	if strings.TrimSpace(head) == "" {
+		for _, name := range shellCommandNames() {
+			if strings.HasPrefix(name, word) {
+				completions = append(completions, name+" ")
+			}
+		}
+		return head, completions, tail
+	}
+
+	for _, path := range sh.completePath(word) {
+		path = strings.ReplaceAll(path, " ", "\\ ")
+		// a completed file ends the word, while a folder can be completed further
+		if !strings.HasSuffix(path, "/") {
+			path += " "
+		}
+		completions = append(completions, path)
+	}
+	return head, completions, tail
+}
+
+// completePath returns the paths that start with the given partial path. Errors complete nothing.
+func (sh *shell) completePath(partial string) []string {
+	dir, prefix := "", partial
+	if i := strings.LastIndex(partial, "/"); i >= 0 {
+		dir, prefix = partial[:i+1], partial[i+1:]
+	}
+
+	project, entries, err := sh.resolve(dir)
+	if err != nil {
+		// the directory could be the owner part of a project ID, which needs the rest of the ID to be resolved
+		owner := strings.TrimSuffix(dir, "/")
+		i := strings.LastIndex(owner, "/")
+		dir, prefix, owner = owner[:i+1], owner[i+1:]+"/"+prefix, owner[i+1:]
+		if owner == "" {
+			return nil
+		}
+		if project, entries, err = sh.resolve(dir); err != nil || project != "" {
+			return nil
+		}
+	}
+
+	completions := make([]string, 0)
+	if project == "" {
+		projects, err := sh.listProjects()
+		if err != nil {
+			return nil
+		}
+		for _, p := range projects {
+			if strings.HasPrefix(p.ID, prefix) {
+				completions = append(completions, dir+p.ID+"/")
+			}
+		}
+		return completions
+	}
+	if len(entries) > 0 && entries[len(entries)-1].Type != cgc.FileTypeFolder {
+		return nil
+	}
+
+	listing, err := sh.list(project, entries)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range listing {
+		if !strings.HasPrefix(entry.Name, prefix) {
+			continue
+		}
+		if entry.Type == cgc.FileTypeFolder {
+			completions = append(completions, dir+entry.Name+"/")
+		} else {
+			completions = append(completions, dir+entry.Name)
+		}
+	}
+	return completions
+}
+
+// splitShellArgs splits the input into arguments on whitespace. Whitespace can be kept in an argument by quoting it
+// with single or double quotes or by escaping it with a backslash.
+func splitShellArgs(input string) ([]string, error) {
+	args := make([]string, 0)
+	var current strings.Builder
+	inArg, escaped := false, false
+	var quote rune
+	for _, r := range input {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			inArg, escaped = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			inArg, quote = true, r
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			inArg = true
+			current.WriteRune(r)
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func init() {
+	shellCommands = map[string]shellCommand{
+		"cd":   {"cd [path]", "change the current directory, to the root if no path is given", shellCd},
+		"pwd":  {"pwd", "print the current directory", shellPwd},
+		"ls":   {"ls [path...]", "list the projects, the contents of folders or the details of files", shellLs},
+		"stat": {"stat path...", "print the details of files or folders as JSON", shellStat},
+		"get":  {"get path [local]", "download a file, into the local working directory by default", shellGet},
+		"put":  {"put local [name]", "upload a local file into the current directory", shellPut},
+		"rm":   {"rm [-f] path...", "delete files, without asking for confirmation if '-f' is given", shellRm},
+		"help": {"help", "print this help", shellHelp},
+		"exit": {"exit", "leave the shell, same as Ctrl-D", shellExit},
+		"quit": {"quit", "leave the shell, same as Ctrl-D", shellExit},
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var filesTagCmd = cli.Command{
+	Name:  "tag",
+	Usage: "A set of commands for managing tags of many files at once.",
+}
+
+var filesTagAddCmd = cli.Command{
+	Name:      "add",
+	Usage:     "Adds tags provided as arguments to the selected files.",
+	ArgsUsage: "TAG...",
+	Action: func(c *cli.Context) error {
+		return retagFiles(c, func(tags []string) []string {
+			return cgc.AddTags(tags, c.Args())
+		})
+	},
+}
+
+var filesTagRemoveCmd = cli.Command{
+	Name:      "remove",
+	Usage:     "Removes tags provided as arguments from the selected files.",
+	ArgsUsage: "TAG...",
+	Action: func(c *cli.Context) error {
+		return retagFiles(c, func(tags []string) []string {
+			return cgc.RemoveTags(tags, c.Args())
+		})
+	},
+}
+
+var filesTagListCmd = cli.Command{
+	Name:  "list",
+	Usage: "Lists tags of the selected files.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		ids, err := selectedFileIDs(c, client)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			file, err := client.StatFile(id)
+			if err != nil {
+				return err
+			}
+			fmt.Println(file.ID, file.Name, strings.Join(file.Tags, ","))
+		}
+		return nil
+	},
+}
+
+// retagFiles computes the new tag set of every selected file using the retag function and applies it to the
+// files where it differs from the current one. Reports what changed for every file. A file that couldn't be retagged
+// doesn't stop the others, the failures are reported on the standard error and summed up in the returned error.
+func retagFiles(c *cli.Context, retag func([]string) []string) error {
+	if len(c.Args()) == 0 {
+		return fmt.Errorf("no tags provided")
+	}
+
+	client := newClient(c)
+
+	ids, err := selectedFileIDs(c, client)
+	if err != nil {
+		return err
+	}
+
+	changed, failed := 0, 0
+	for _, id := range ids {
+		file, err := client.StatFile(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "retagging '%s' failed: %s\n", id, err.Error())
+			failed++
+			continue
+		}
+
+		tags := retag(file.Tags)
+		added, removed := cgc.DiffTags(file.Tags, tags)
+		if len(added) == 0 && len(removed) == 0 {
+			fmt.Println(file.ID, "unchanged")
+			continue
+		}
+
+		if err := client.UpdateFileTags(file.ID, tags); err != nil {
+			fmt.Fprintf(os.Stderr, "retagging '%s' failed: %s\n", file.ID, err.Error())
+			failed++
+			continue
+		}
+		changed++
+		fmt.Printf("%s added: [%s] removed: [%s]\n", file.ID, strings.Join(added, ","), strings.Join(removed, ","))
+	}
+
+	fmt.Printf("changed %d of %d files\n", changed, len(ids))
+	if failed > 0 {
+		return fmt.Errorf("retagging %d out of %d files failed", failed, len(ids))
+	}
+	return nil
+}
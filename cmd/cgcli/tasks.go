@@ -0,0 +1,603 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var tasksCmd = cli.Command{
+	Usage: "A set of commands for creating, running and inspecting tasks.",
+	Name:  "tasks",
+}
+
+var tasksCreateCmd = cli.Command{
+	Name: "create",
+	Usage: fmt.Sprintf(
+		"Creates a draft task running an app provided with '%s' flag in a project provided with '%s' flag.",
+		appFlag.Name, projectFlag.Name,
+	),
+	UsageText: fmt.Sprintf(
+		"Inputs are read from a JSON or YAML file provided with '%s' flag, and can be overridden with '%s' flag "+
+			"in format 'port=FILEID' (repeat the port to pass an array of files) or '%s' flag in format 'port=value'.",
+		inputsFileFlag.Name, inputFlag.Name, paramFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		inputs, err := taskInputs(c)
+		if err != nil {
+			return err
+		}
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+
+		draft := cgc.Task{
+			Name:        c.String(taskNameFlag.Name),
+			Description: c.String(descriptionFlag.Name),
+			Project:     projectID,
+			App:         c.String(appFlag.Name),
+			Inputs:      inputs,
+		}
+		if batchInput := c.String(batchInputFlag.Name); batchInput != "" {
+			draft.BatchInput = batchInput
+			draft.BatchBy = cgc.BatchByCriteria(c.StringSlice(batchByFlag.Name)...)
+		}
+
+		if !c.Bool(noValidateFlag.Name) {
+			problems, err := client.ValidateTaskInputs(draft)
+			if err != nil {
+				return err
+			}
+			if err := cgc.InputError(problems); err != nil {
+				return err
+			}
+		}
+
+		task, err := client.CreateTask(draft, c.Bool(runFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewEncoder(os.Stdout).Encode(task); err != nil {
+			return err
+		}
+		if c.Bool(runFlag.Name) && c.Bool(waitFlag.Name) {
+			return waitTask(c, client, task.ID)
+		}
+		return nil
+	},
+}
+
+var tasksValidateCmd = cli.Command{
+	Name: "validate",
+	Usage: fmt.Sprintf(
+		"Validates task inputs against the inputs of an app provided with '%s' flag without creating the task.",
+		appFlag.Name,
+	),
+	UsageText: "Takes the same flags as 'create' command. Reports inputs on unknown ports, missing required inputs, " +
+		"values that don't fit the type of the port and secondary files missing from the project.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		inputs, err := taskInputs(c)
+		if err != nil {
+			return err
+		}
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+
+		problems, err := client.ValidateTaskInputs(cgc.Task{
+			Project:    projectID,
+			App:        c.String(appFlag.Name),
+			Inputs:     inputs,
+			BatchInput: c.String(batchInputFlag.Name),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("found %d problems with task inputs", len(problems))
+		}
+		return nil
+	},
+}
+
+var tasksFanoutCmd = cli.Command{
+	Name: "fanout",
+	Usage: fmt.Sprintf(
+		"Groups files of a project provided with '%s' flag by a metadata field provided with '%s' flag and creates "+
+			"one task per group.",
+		projectFlag.Name, groupByFlag.Name,
+	),
+	UsageText: fmt.Sprintf(
+		"Files of every group are passed to the input port provided with '%s' flag, while the other inputs are "+
+			"the same for every task and given the same way as with 'create' command. Files can be narrowed down "+
+			"by tags and metadata. Prints a report of every submission.",
+		groupInputFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		inputs, err := taskInputs(c)
+		if err != nil {
+			return err
+		}
+		filter, err := fileFilter(c)
+		if err != nil {
+			return err
+		}
+		// the name flag holds the name of the tasks here, files are only filtered by tags and metadata
+		filter.Name = ""
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		listed, err := client.FilterFiles(projectID, filter)
+		if err != nil {
+			return err
+		}
+		files := make([]cgc.File, 0, len(listed))
+		for _, f := range listed {
+			file, err := client.StatFile(f.ID)
+			if err != nil {
+				return err
+			}
+			files = append(files, file)
+		}
+
+		groups, ungrouped := cgc.GroupFiles(files, strings.TrimPrefix(c.String(groupByFlag.Name), "metadata."))
+		for _, file := range ungrouped {
+			fmt.Fprintf(os.Stderr, "skipping %s %s, '%s' not set\n", file.ID, file.Name, c.String(groupByFlag.Name))
+		}
+
+		name := c.String(taskNameFlag.Name)
+		if name == "" {
+			name = c.String(appFlag.Name)
+		}
+		port := c.String(groupInputFlag.Name)
+		tasks := make([]cgc.Task, 0, len(groups))
+		for _, group := range groups {
+			groupInputs := make(map[string]interface{})
+			for k, v := range inputs {
+				groupInputs[k] = v
+			}
+			groupFiles := make([]interface{}, 0, len(group.Files))
+			for _, file := range group.Files {
+				groupFiles = append(groupFiles, cgc.FileInput(file.ID))
+			}
+			groupInputs[port] = groupFiles
+
+			tasks = append(tasks, cgc.Task{
+				Name:        fmt.Sprintf("%s - %s", name, group.Value),
+				Description: c.String(descriptionFlag.Name),
+				Project:     projectID,
+				App:         c.String(appFlag.Name),
+				Inputs:      groupInputs,
+			})
+		}
+
+		failed := 0
+		submissions := client.SubmitTasks(tasks, c.Bool(runFlag.Name), c.Int(concurrencyFlag.Name))
+		for i, s := range submissions {
+			if s.Err != nil {
+				failed++
+				fmt.Printf("%s %d files FAILED %s\n", groups[i].Value, len(groups[i].Files), s.Err)
+				continue
+			}
+			fmt.Printf("%s %d files %s %s\n", groups[i].Value, len(groups[i].Files), s.Task.ID, s.Task.Status)
+		}
+
+		fmt.Printf("submitted %d of %d tasks, %d files skipped\n", len(tasks)-failed, len(tasks), len(ungrouped))
+		if failed > 0 {
+			return fmt.Errorf("%d task submissions failed", failed)
+		}
+		return nil
+	},
+}
+
+var tasksRunCmd = cli.Command{
+	Name:  "run",
+	Usage: fmt.Sprintf("Runs a draft task provided with '%s' flag.", taskFlag.Name),
+	Action: func(c *cli.Context) error {
+		if err := taskAction(c, cgc.Client.RunTask); err != nil {
+			return err
+		}
+		if c.Bool(waitFlag.Name) {
+			return waitTask(c, newClient(c), c.String(taskFlag.Name))
+		}
+		return nil
+	},
+}
+
+var tasksWaitCmd = cli.Command{
+	Name:  "wait",
+	Usage: fmt.Sprintf("Waits for a task provided with '%s' flag to finish, printing its progress.", taskFlag.Name),
+	UsageText: fmt.Sprintf(
+		"Exits with code %d if the task completed, %d if it failed, %d if it was aborted and %d on timeout.",
+		0, exitTaskFailed, exitTaskAborted, exitTaskTimeout,
+	),
+	Action: func(c *cli.Context) error {
+		return waitTask(c, newClient(c), c.String(taskFlag.Name))
+	},
+}
+
+var tasksAbortCmd = cli.Command{
+	Name:  "abort",
+	Usage: fmt.Sprintf("Aborts a queued or running task provided with '%s' flag.", taskFlag.Name),
+	Action: func(c *cli.Context) error {
+		return taskAction(c, cgc.Client.AbortTask)
+	},
+}
+
+var tasksCloneCmd = cli.Command{
+	Name: "clone",
+	Usage: fmt.Sprintf(
+		"Creates a new draft task with the same app and inputs as a task provided with '%s' flag.",
+		taskFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		return taskAction(c, cgc.Client.CloneTask)
+	},
+}
+
+var tasksStatCmd = cli.Command{
+	Name: "stat",
+	Usage: fmt.Sprintf(
+		"Prints a JSON string representing information about a task provided with '%s' flag.",
+		taskFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		return taskAction(c, cgc.Client.StatTask)
+	},
+}
+
+var tasksListCmd = cli.Command{
+	Name: "list",
+	Usage: fmt.Sprintf(
+		"Lists tasks, optionally only the ones in a project provided with '%s' flag or with a status provided "+
+			"with '%s' flag.",
+		projectFlag.Name, statusFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		filter := cgc.TaskFilter{
+			Project: projectID,
+			Status:  strings.ToUpper(c.String(statusFlag.Name)),
+		}
+		it := client.IterateTasks(filter, cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for first := true; it.Next(); first = false {
+			if first {
+				reportTotal(it.Total())
+			}
+			task := it.Item()
+			fmt.Println(task.ID, task.Status, task.Name)
+		}
+		return it.Err()
+	},
+}
+
+var tasksOutputsCmd = cli.Command{
+	Name:  "outputs",
+	Usage: "A set of commands for working with files produced by a task.",
+}
+
+var tasksOutputsListCmd = cli.Command{
+	Name:  "list",
+	Usage: fmt.Sprintf("Lists every file produced by a task provided with '%s' flag.", taskFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		files, err := taskOutputFiles(c, client)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			fmt.Println(file.Port, file.Path, file.FileID)
+		}
+		return nil
+	},
+}
+
+var tasksOutputsDownloadCmd = cli.Command{
+	Name: "download",
+	Usage: fmt.Sprintf(
+		"Downloads every file produced by a task provided with '%s' flag into a directory provided with '%s' flag.",
+		taskFlag.Name, destFlag.Name,
+	),
+	UsageText: "Files of every output port are downloaded into a subdirectory named after the port, keeping the " +
+		"structure of the output folders.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		files, err := taskOutputFiles(c, client)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			dest, err := outputPath(c.String(destFlag.Name), file)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return fmt.Errorf("creating '%s' directory failed: %s", filepath.Dir(dest), err.Error())
+			}
+			if err := client.DownloadFile(file.FileID, dest); err != nil {
+				return err
+			}
+			fmt.Println(dest)
+		}
+		return nil
+	},
+}
+
+// outputPath returns the path the output file is downloaded to under the dest directory. The port and the path come
+// from the API, so a path that would end up outside of dest (e.g. one with '..' in it) is refused.
+func outputPath(dest string, file cgc.OutputFile) (string, error) {
+	target := filepath.Join(dest, file.Port, filepath.FromSlash(file.Path))
+	rel, err := filepath.Rel(dest, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("output '%s' of port '%s' points outside of '%s'", file.Path, file.Port, dest)
+	}
+	return target, nil
+}
+
+// taskOutputFiles resolves the files produced by the task provided with the task flag, on the ports provided with
+// the port flag if any.
+func taskOutputFiles(c *cli.Context, client cgc.Client) ([]cgc.OutputFile, error) {
+	task, err := client.StatTask(c.String(taskFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != cgc.TaskCompleted {
+		fmt.Fprintf(os.Stderr, "warning: task '%s' is %s, outputs may be incomplete\n", task.ID, task.Status)
+	}
+
+	return client.TaskOutputFiles(task, c.StringSlice(portFlag.Name)...)
+}
+
+var taskFlag = cli.StringFlag{
+	Usage: "represents the task ID",
+	Name:  "task",
+}
+var appFlag = cli.StringFlag{
+	Usage: "represents the app ID",
+	Name:  "app",
+}
+var taskNameFlag = cli.StringFlag{
+	Usage: "name of the task",
+	Name:  "name",
+}
+var inputFlag = cli.StringSliceFlag{
+	Usage: "file input in format 'port=FILEID', can be repeated",
+	Name:  "input",
+}
+var paramFlag = cli.StringSliceFlag{
+	Usage: "non-file input in format 'port=value', can be repeated",
+	Name:  "param",
+}
+var inputsFileFlag = cli.StringFlag{
+	Usage: "path to a JSON or YAML file with task inputs",
+	Name:  "inputs",
+}
+var runFlag = cli.BoolFlag{
+	Usage: "run the task right after creating it",
+	Name:  "run",
+}
+var noValidateFlag = cli.BoolFlag{
+	Usage: "don't validate task inputs against the app before creating the task",
+	Name:  "no-validate",
+}
+var portFlag = cli.StringSliceFlag{
+	Usage: "only use the outputs on this port, can be repeated",
+	Name:  "port",
+}
+var batchInputFlag = cli.StringFlag{
+	Usage: "input port to split into child tasks, making the task a batch task",
+	Name:  "batch-input",
+}
+var batchByFlag = cli.StringSliceFlag{
+	Usage: "field to group the batch input by (e.g. 'metadata.sample_id'), can be repeated, " +
+		"every file gets its own child task if not set",
+	Name: "batch-by",
+}
+var groupByFlag = cli.StringFlag{
+	Usage: "metadata field to group the files by (e.g. 'sample_id')",
+	Name:  "group-by",
+}
+var groupInputFlag = cli.StringFlag{
+	Usage: "input port that gets the files of a group",
+	Name:  "group-input",
+}
+var concurrencyFlag = cli.IntFlag{
+	Usage: "maximum number of requests made at the same time",
+	Name:  "concurrency",
+	Value: 4,
+}
+var waitFlag = cli.BoolFlag{
+	Usage: "wait for the task to finish, printing its progress",
+	Name:  "wait",
+}
+var timeoutFlag = cli.DurationFlag{
+	Usage: "stop waiting for the task after this long (e.g. '90m'), 0 waits forever",
+	Name:  "timeout",
+}
+var statusFlag = cli.StringFlag{
+	Usage: "task status, one of 'draft', 'queued', 'running', 'completed', 'failed' or 'aborted'",
+	Name:  "status",
+}
+
+// Exit codes of the commands that wait for a task to finish.
+const (
+	exitTaskFailed  = 2
+	exitTaskAborted = 3
+	exitTaskTimeout = 4
+)
+
+// waitTask waits for the task with taskID to finish, printing its status and progress as they change. Returns an
+// error with an exit code reflecting the final status of the task, or the timeout if it was reached.
+func waitTask(c *cli.Context, client cgc.Client, taskID string) error {
+	ctx := context.Background()
+	if timeout := c.Duration(timeoutFlag.Name); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	task, err := client.WaitTask(ctx, taskID, func(t cgc.Task) {
+		line := fmt.Sprintf("%s %s %s", time.Now().Format(time.RFC3339), t.ID, t.Status)
+		if s := t.ExecutionStatus; s != nil {
+			line += fmt.Sprintf(
+				" steps: %d/%d jobs: %d queued, %d running, %d completed, %d failed",
+				s.StepsCompleted, s.StepsTotal, s.Queued, s.Running, s.Completed, s.Failed,
+			)
+			if s.Message != "" {
+				line += fmt.Sprintf(" (%s)", s.Message)
+			}
+		}
+		fmt.Println(line)
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return cli.NewExitError(err.Error(), exitTaskTimeout)
+		}
+		return err
+	}
+
+	switch task.Status {
+	case cgc.TaskFailed:
+		return cli.NewExitError(fmt.Sprintf("task '%s' failed", task.ID), exitTaskFailed)
+	case cgc.TaskAborted:
+		return cli.NewExitError(fmt.Sprintf("task '%s' was aborted", task.ID), exitTaskAborted)
+	}
+	return nil
+}
+
+// taskAction calls action with the task provided with the task flag and prints the resulting task as JSON.
+func taskAction(c *cli.Context, action func(cgc.Client, string) (cgc.Task, error)) error {
+	client := newClient(c)
+
+	task, err := action(client, c.String(taskFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(task)
+}
+
+// taskInputs builds the task inputs out of the inputs file and the input flags. Inputs given with flags take
+// precedence over the ones from the file.
+func taskInputs(c *cli.Context) (map[string]interface{}, error) {
+	inputs := make(map[string]interface{})
+
+	if path := c.String(inputsFileFlag.Name); path != "" {
+		if err := decodeYAMLFile(path, &inputs); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make(map[string][]interface{})
+	order := make([]string, 0)
+	for _, input := range c.StringSlice(inputFlag.Name) {
+		kv := strings.SplitN(input, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed '%s' flag: %s", inputFlag.Name, input)
+		}
+		if _, ok := files[kv[0]]; !ok {
+			order = append(order, kv[0])
+		}
+		files[kv[0]] = append(files[kv[0]], cgc.FileInput(kv[1]))
+	}
+	for _, port := range order {
+		if len(files[port]) == 1 {
+			inputs[port] = files[port][0]
+		} else {
+			inputs[port] = files[port]
+		}
+	}
+
+	for _, param := range c.StringSlice(paramFlag.Name) {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed '%s' flag: %s", paramFlag.Name, param)
+		}
+		inputs[kv[0]] = parseParam(kv[1])
+	}
+
+	return inputs, nil
+}
+
+// parseParam converts a value of a non-file input to the type it should have when encoded in JSON.
+func parseParam(value string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return value
+	}
+	return v
+}
+
+func init() {
+	tasksCreateCmd.Flags = []cli.Flag{
+		projectFlag, appFlag, taskNameFlag, descriptionFlag, inputsFileFlag, inputFlag, paramFlag,
+		batchInputFlag, batchByFlag, noValidateFlag, runFlag, waitFlag, timeoutFlag,
+	}
+	tasksValidateCmd.Flags = []cli.Flag{
+		projectFlag, appFlag, inputsFileFlag, inputFlag, paramFlag, batchInputFlag,
+	}
+	tasksFanoutCmd.Flags = []cli.Flag{
+		projectFlag, appFlag, taskNameFlag, descriptionFlag, inputsFileFlag, inputFlag, paramFlag,
+		groupByFlag, groupInputFlag, withTagFlag, whereFlag, concurrencyFlag, runFlag,
+	}
+	tasksRunCmd.Flags = []cli.Flag{taskFlag, waitFlag, timeoutFlag}
+	tasksWaitCmd.Flags = []cli.Flag{taskFlag, timeoutFlag}
+	tasksAbortCmd.Flags = []cli.Flag{taskFlag}
+	tasksCloneCmd.Flags = []cli.Flag{taskFlag}
+	tasksStatCmd.Flags = []cli.Flag{taskFlag}
+	tasksLogsCmd.Flags = []cli.Flag{taskFlag, jobFlag, logFlag, followFlag}
+	tasksOutputsListCmd.Flags = []cli.Flag{taskFlag, portFlag}
+	tasksOutputsDownloadCmd.Flags = []cli.Flag{taskFlag, portFlag, destFlag}
+
+	tasksOutputsCmd.Subcommands = []cli.Command{
+		tasksOutputsListCmd,
+		tasksOutputsDownloadCmd,
+	}
+	tasksListCmd.Flags = []cli.Flag{projectFlag, statusFlag, limitFlag}
+
+	tasksCmd.Subcommands = []cli.Command{
+		tasksCreateCmd,
+		tasksValidateCmd,
+		tasksFanoutCmd,
+		tasksRunCmd,
+		tasksWaitCmd,
+		tasksAbortCmd,
+		tasksCloneCmd,
+		tasksListCmd,
+		tasksStatCmd,
+		tasksLogsCmd,
+		tasksOutputsCmd,
+	}
+}
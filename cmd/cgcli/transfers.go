@@ -0,0 +1,429 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var importsCmd = cli.Command{
+	Usage: "A set of commands for importing files from volumes into projects.",
+	Name:  "imports",
+}
+
+var importsStartCmd = cli.Command{
+	Name: "start",
+	Usage: fmt.Sprintf(
+		"Imports an object provided with '%s' flag, or every object under the prefix provided with '%s' flag, "+
+			"from a volume provided with '%s' flag into a project or a folder.",
+		locationFlag.Name, prefixFlag.Name, volumeFlag.Name,
+	),
+	UsageText: fmt.Sprintf(
+		"Files are imported into a project provided with '%s' flag or a folder provided with '%s' flag, keeping "+
+			"the names of the objects. Objects with the same name under the prefix are refused unless '%s' flag is "+
+			"set. The IDs of the started import jobs are printed.",
+		projectFlag.Name, parentFlag.Name, autorenameFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		volumeID := c.String(volumeFlag.Name)
+		locations, err := volumeLocations(c, client, volumeID)
+		if err != nil {
+			return err
+		}
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		parentID := c.String(parentFlag.Name)
+		if parentID != "" {
+			if parentID, err = client.ResolveFile(parentID); err != nil {
+				return err
+			}
+		}
+
+		if err := checkImportNames(c, locations); err != nil {
+			return err
+		}
+
+		reqs := make([]cgc.ImportRequest, 0, len(locations))
+		for _, location := range locations {
+			reqs = append(reqs, cgc.ImportRequest{
+				Source: cgc.VolumeLocation{Volume: volumeID, Location: location},
+				Destination: cgc.ImportDestination{
+					Project: projectID,
+					Parent:  parentID,
+				},
+				Overwrite:  c.Bool(overwriteFlag.Name),
+				Autorename: c.Bool(autorenameFlag.Name),
+			})
+		}
+		submissions := client.StartImports(reqs)
+		ids := make([]string, 0, len(submissions))
+		for _, s := range submissions {
+			if s.Err != nil {
+				fmt.Fprintf(os.Stderr, "importing '%s' failed: %s\n", s.Request.Source.Location, s.Err.Error())
+				continue
+			}
+			fmt.Println(s.Import.ID, s.Request.Source.Location)
+			ids = append(ids, s.Import.ID)
+		}
+		return startedTransfers(c, "imports", ids, len(submissions), func(ctx context.Context, id string) (string, error) {
+			job, err := client.WaitImport(ctx, id, func(i cgc.Import) { printImport(i) })
+			return job.State, err
+		})
+	},
+}
+
+var importsStatusCmd = cli.Command{
+	Name:  "status",
+	Usage: fmt.Sprintf("Prints the state of import jobs provided with '%s' flag.", importFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		ids := c.StringSlice(importFlag.Name)
+		if c.Bool(transferWaitFlag.Name) {
+			return waitTransfers(c, ids, func(ctx context.Context, id string) (string, error) {
+				job, err := client.WaitImport(ctx, id, func(i cgc.Import) { printImport(i) })
+				return job.State, err
+			})
+		}
+
+		jobs, err := client.StatImports(ids)
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			printImport(job)
+		}
+		return nil
+	},
+}
+
+var importsListCmd = cli.Command{
+	Name:  "list",
+	Usage: "Lists import jobs, optionally only the ones from a volume, into a project or in a state.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		projectID, err := resolveProject(c, client)
+		if err != nil {
+			return err
+		}
+		filter := cgc.TransferFilter{
+			Volume:  c.String(volumeFlag.Name),
+			Project: projectID,
+			State:   c.String(stateFlag.Name),
+		}
+		it := client.IterateImports(filter, cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for first := true; it.Next(); first = false {
+			if first {
+				reportTotal(it.Total())
+			}
+			printImport(it.Item())
+		}
+		return it.Err()
+	},
+}
+
+var exportsCmd = cli.Command{
+	Usage: "A set of commands for exporting files from projects to volumes.",
+	Name:  "exports",
+}
+
+var exportsStartCmd = cli.Command{
+	Name:  "start",
+	Usage: fmt.Sprintf("Exports the selected files to a volume provided with '%s' flag.", volumeFlag.Name),
+	UsageText: fmt.Sprintf(
+		"Files are selected the same way as for tagging. A single file is exported to the location provided with "+
+			"'%s' flag, otherwise files are exported under the prefix provided with '%s' flag, keeping their names. "+
+			"The IDs of the started export jobs are printed.",
+		locationFlag.Name, prefixFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		fileIDs, err := selectedFileIDs(c, client)
+		if err != nil {
+			return err
+		}
+		location := c.String(locationFlag.Name)
+		if location != "" && len(fileIDs) > 1 {
+			return fmt.Errorf("'%s' flag can only be used with a single file, use '%s' flag instead",
+				locationFlag.Name, prefixFlag.Name)
+		}
+
+		reqs := make([]cgc.ExportRequest, 0, len(fileIDs))
+		for _, fileID := range fileIDs {
+			if location == "" {
+				file, err := client.StatFile(fileID)
+				if err != nil {
+					return err
+				}
+				location = path.Join(c.String(prefixFlag.Name), file.Name)
+			}
+			reqs = append(reqs, cgc.ExportRequest{
+				Source:      cgc.ExportSource{File: fileID},
+				Destination: cgc.VolumeLocation{Volume: c.String(volumeFlag.Name), Location: location},
+				Overwrite:   c.Bool(overwriteFlag.Name),
+			})
+			location = ""
+		}
+		submissions := client.StartExports(reqs)
+		ids := make([]string, 0, len(submissions))
+		for _, s := range submissions {
+			if s.Err != nil {
+				fmt.Fprintf(os.Stderr, "exporting '%s' failed: %s\n", s.Request.Source.File, s.Err.Error())
+				continue
+			}
+			fmt.Println(s.Export.ID, s.Request.Destination.Location)
+			ids = append(ids, s.Export.ID)
+		}
+		return startedTransfers(c, "exports", ids, len(submissions), func(ctx context.Context, id string) (string, error) {
+			job, err := client.WaitExport(ctx, id, func(e cgc.Export) { printExport(e) })
+			return job.State, err
+		})
+	},
+}
+
+var exportsStatusCmd = cli.Command{
+	Name:  "status",
+	Usage: fmt.Sprintf("Prints the state of export jobs provided with '%s' flag.", exportFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		ids := c.StringSlice(exportFlag.Name)
+		if c.Bool(transferWaitFlag.Name) {
+			return waitTransfers(c, ids, func(ctx context.Context, id string) (string, error) {
+				job, err := client.WaitExport(ctx, id, func(e cgc.Export) { printExport(e) })
+				return job.State, err
+			})
+		}
+
+		jobs, err := client.StatExports(ids)
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			printExport(job)
+		}
+		return nil
+	},
+}
+
+var exportsListCmd = cli.Command{
+	Name:  "list",
+	Usage: "Lists export jobs, optionally only the ones to a volume or in a state.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		filter := cgc.TransferFilter{
+			Volume: c.String(volumeFlag.Name),
+			State:  c.String(stateFlag.Name),
+		}
+		it := client.IterateExports(filter, cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for first := true; it.Next(); first = false {
+			if first {
+				reportTotal(it.Total())
+			}
+			printExport(it.Item())
+		}
+		return it.Err()
+	},
+}
+
+// Exit codes used when waiting for import or export jobs.
+const (
+	exitTransferFailed  = 2
+	exitTransferTimeout = 4
+)
+
+// volumeLocations returns the location provided with the location flag, or the locations of all the objects under
+// the prefix provided with the prefix flag.
+func volumeLocations(c *cli.Context, client cgc.Client, volumeID string) ([]string, error) {
+	if location := c.String(locationFlag.Name); location != "" {
+		return []string{location}, nil
+	}
+	if !c.IsSet(prefixFlag.Name) {
+		return nil, fmt.Errorf("either '%s' or '%s' flag has to be set", locationFlag.Name, prefixFlag.Name)
+	}
+
+	objects, err := client.VolumeObjects(volumeID, c.String(prefixFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects found under prefix '%s'", c.String(prefixFlag.Name))
+	}
+	locations := make([]string, 0, len(objects))
+	for _, object := range objects {
+		locations = append(locations, object.Location)
+	}
+	return locations, nil
+}
+
+// startedTransfers waits for the jobs in ids if the wait flag is set, even if some of the total jobs failed to start.
+// Those are reported afterwards, with the exit code of a failed job. An error waiting for the started jobs is printed
+// in that case, unless it's the timeout expiring, which keeps its own exit code.
+func startedTransfers(c *cli.Context, kind string, ids []string, total int,
+	wait func(context.Context, string) (string, error)) error {
+	var waitErr error
+	if c.Bool(transferWaitFlag.Name) {
+		waitErr = waitTransfers(c, ids, wait)
+	}
+	failed := total - len(ids)
+	if failed == 0 {
+		return waitErr
+	}
+	if waitErr != nil {
+		var exitErr cli.ExitCoder
+		if errors.As(waitErr, &exitErr) && exitErr.ExitCode() == exitTransferTimeout {
+			return waitErr
+		}
+		fmt.Fprintln(os.Stderr, waitErr.Error())
+	}
+	return cli.NewExitError(fmt.Sprintf("%d out of %d %s failed to start", failed, total, kind), exitTransferFailed)
+}
+
+// checkImportNames makes sure that no two objects under the prefix would be imported under the same name, which is
+// the case for objects with the same name in different sub-folders (e.g. 'a/x.bam' and 'b/x.bam'). Such names are
+// only allowed if the autorename flag is set.
+func checkImportNames(c *cli.Context, locations []string) error {
+	if c.Bool(autorenameFlag.Name) {
+		return nil
+	}
+	seen := make(map[string]string, len(locations))
+	for _, location := range locations {
+		name := path.Base(location)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("objects '%s' and '%s' would both be imported as '%s', use '%s' flag to rename them",
+				other, location, name, autorenameFlag.Name)
+		}
+		seen[name] = location
+	}
+	return nil
+}
+
+// waitTransfers waits for every job in ids to finish, one after another, using wait. The timeout applies to all the
+// jobs together. Returns an error with a distinct exit code if any of the jobs failed or the timeout expired.
+func waitTransfers(c *cli.Context, ids []string, wait func(context.Context, string) (string, error)) error {
+	ctx := context.Background()
+	if timeout := c.Duration(transferTimeoutFlag.Name); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	failed := 0
+	for _, id := range ids {
+		state, err := wait(ctx, id)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return cli.NewExitError(err.Error(), exitTransferTimeout)
+			}
+			return err
+		}
+		if state == cgc.TransferFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return cli.NewExitError(fmt.Sprintf("%d out of %d jobs failed", failed, len(ids)), exitTransferFailed)
+	}
+	return nil
+}
+
+// printImport prints a line describing the state of the import job, along with the imported file or the error.
+func printImport(job cgc.Import) {
+	line := fmt.Sprintf("%s %s %s %s:%s", time.Now().Format(time.RFC3339), job.ID, job.State,
+		job.Source.Volume, job.Source.Location)
+	if job.Result != nil {
+		line += " -> " + job.Result.ID
+	}
+	if job.Error != nil {
+		line += fmt.Sprintf(" (%s)", job.Error.Message)
+	}
+	fmt.Println(line)
+}
+
+// printExport prints a line describing the state of the export job, along with the error if it failed.
+func printExport(job cgc.Export) {
+	line := fmt.Sprintf("%s %s %s %s -> %s:%s", time.Now().Format(time.RFC3339), job.ID, job.State,
+		job.Source.File, job.Destination.Volume, job.Destination.Location)
+	if job.Error != nil {
+		line += fmt.Sprintf(" (%s)", job.Error.Message)
+	}
+	fmt.Println(line)
+}
+
+var locationFlag = cli.StringFlag{
+	Usage: "location of the object on the volume",
+	Name:  "location",
+}
+var parentFlag = cli.StringFlag{
+	Usage: "represents the folder to import into, by ID or path ('owner/project/path/to/folder')",
+	Name:  "parent",
+}
+var overwriteFlag = cli.BoolFlag{
+	Usage: "overwrite the files that already exist",
+	Name:  "overwrite",
+}
+var autorenameFlag = cli.BoolFlag{
+	Usage: "give the imported files new names if files with the same names already exist",
+	Name:  "autorename",
+}
+var importFlag = cli.StringSliceFlag{
+	Usage: "represents the import job ID, can be repeated",
+	Name:  "import",
+}
+var exportFlag = cli.StringSliceFlag{
+	Usage: "represents the export job ID, can be repeated",
+	Name:  "export",
+}
+var stateFlag = cli.StringFlag{
+	Usage: fmt.Sprintf(
+		"state of the jobs, one of '%s', '%s', '%s' or '%s'",
+		cgc.TransferPending, cgc.TransferRunning, cgc.TransferCompleted, cgc.TransferFailed,
+	),
+	Name: "state",
+}
+var transferWaitFlag = cli.BoolFlag{
+	Usage: "wait for the jobs to finish, printing their state as it changes",
+	Name:  "wait",
+}
+var transferTimeoutFlag = cli.DurationFlag{
+	Usage: "stop waiting for the jobs after this long (e.g. '90m'), 0 waits forever",
+	Name:  "timeout",
+}
+
+func init() {
+	importsStartCmd.Flags = []cli.Flag{
+		volumeFlag, locationFlag, prefixFlag, projectFlag, parentFlag, overwriteFlag, autorenameFlag,
+		transferWaitFlag, transferTimeoutFlag,
+	}
+	importsStatusCmd.Flags = []cli.Flag{importFlag, transferWaitFlag, transferTimeoutFlag}
+	importsListCmd.Flags = []cli.Flag{volumeFlag, projectFlag, stateFlag, limitFlag}
+	exportsStartCmd.Flags = append([]cli.Flag{
+		volumeFlag, locationFlag, prefixFlag, overwriteFlag, transferWaitFlag, transferTimeoutFlag,
+	}, fileSelectionFlags...)
+	exportsStatusCmd.Flags = []cli.Flag{exportFlag, transferWaitFlag, transferTimeoutFlag}
+	exportsListCmd.Flags = []cli.Flag{volumeFlag, stateFlag, limitFlag}
+
+	importsCmd.Subcommands = []cli.Command{
+		importsStartCmd,
+		importsStatusCmd,
+		importsListCmd,
+	}
+	exportsCmd.Subcommands = []cli.Command{
+		exportsStartCmd,
+		exportsStatusCmd,
+		exportsListCmd,
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+// exitInvalidToken is the exit code of 'whoami --check' when the API rejects the token. Any other failure (e.g. the
+// API being unreachable) exits with 1, so a bad secret can be told apart from an outage.
+const exitInvalidToken = 2
+
+var whoamiCmd = cli.Command{
+	Name:  "whoami",
+	Usage: "Prints the user the token belongs to and the API the requests are made to.",
+	UsageText: fmt.Sprintf(
+		"With '%s' flag nothing is printed. Exits with %d if the token is invalid and with 1 if it couldn't be "+
+			"checked.",
+		checkFlag.Name, exitInvalidToken,
+	),
+	Action: func(c *cli.Context) error {
+		// a cached answer could hide a token that was revoked in the meantime
+		client := cgc.New(c.GlobalString(tokenFlag.Name))
+
+		user, err := client.User()
+		// only the exit code is meant to be looked at (e.g. when validating secrets in CI), so the output can't leak
+		// anything about the token or its holder
+		if c.Bool(checkFlag.Name) {
+			var statusErr *cgc.StatusError
+			if errors.As(err, &statusErr) &&
+				(statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden) {
+				return cli.NewExitError(fmt.Sprintf("token is invalid: %s", err.Error()), exitInvalidToken)
+			}
+			if err != nil {
+				return cli.NewExitError(fmt.Sprintf("checking token failed: %s", err.Error()), 1)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("username:    %s\n", user.Username)
+		fmt.Printf("email:       %s\n", user.Email)
+		fmt.Printf("affiliation: %s\n", user.Affiliation)
+		fmt.Printf("division:    %s\n", user.Division)
+		// there are no configuration profiles and the token always comes from the flag, so the endpoint is all
+		// there is to tell which API is used
+		fmt.Printf("endpoint:    %s\n", client.BaseURL())
+		return nil
+	},
+}
+
+var checkFlag = cli.BoolFlag{
+	Usage: "only check whether the token is valid",
+	Name:  "check",
+}
+
+func init() {
+	whoamiCmd.Flags = []cli.Flag{checkFlag}
+}
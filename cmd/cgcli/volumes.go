@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/doza-daniel/cgcli/cgc"
+	"github.com/urfave/cli"
+)
+
+var volumesCmd = cli.Command{
+	Usage: "A set of commands for managing volumes, the connections to cloud storage buckets.",
+	Name:  "volumes",
+}
+
+var volumesListCmd = cli.Command{
+	Name:  "list",
+	Usage: "Lists volumes available to the user.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		it := client.IterateVolumes(cgc.ListOptions{Limit: c.Int(limitFlag.Name)})
+		for first := true; it.Next(); first = false {
+			if first {
+				reportTotal(it.Total())
+			}
+			volume := it.Item()
+			fmt.Println(volume.ID, volume.Service.Type, volume.AccessMode, volume.Active)
+		}
+
+		return it.Err()
+	},
+}
+
+var volumesStatCmd = cli.Command{
+	Name: "stat",
+	Usage: fmt.Sprintf(
+		"Prints a JSON string representing information about a volume provided with '%s' flag.",
+		volumeFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		volume, err := client.StatVolume(c.String(volumeFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(volume.Redacted())
+	},
+}
+
+var volumesCreateCmd = cli.Command{
+	Name:  "create",
+	Usage: "Connects a bucket as a new volume and prints a JSON string representing it.",
+	UsageText: fmt.Sprintf(
+		"S3 buckets are accessed either with '%s' and '%s' flags or by assuming the role provided with '%s' flag, "+
+			"while GCS buckets are accessed with the service account key provided with '%s' flag. Secrets are "+
+			"only ever read from files and are never printed.",
+		accessKeyIDFlag.Name, secretAccessKeyFileFlag.Name, roleARNFlag.Name, gcsKeyFileFlag.Name,
+	),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		credentials, err := volumeCredentials(c)
+		if err != nil {
+			return err
+		}
+		volume, err := client.CreateVolume(cgc.Volume{
+			Name:        c.String(volumeNameFlag.Name),
+			Description: c.String(descriptionFlag.Name),
+			AccessMode:  c.String(accessModeFlag.Name),
+			Service: cgc.VolumeService{
+				Type:        c.String(serviceTypeFlag.Name),
+				Bucket:      c.String(bucketFlag.Name),
+				Prefix:      c.String(prefixFlag.Name),
+				Endpoint:    c.String(endpointFlag.Name),
+				Credentials: credentials,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(volume.Redacted())
+	},
+}
+
+var volumesUpdateCmd = cli.Command{
+	Name: "update",
+	Usage: fmt.Sprintf(
+		"Updates the description, the access mode or the credentials of a volume provided with '%s' flag.",
+		volumeFlag.Name,
+	),
+	UsageText: "Only the fields provided with flags are updated. Credentials are replaced as a whole, using the " +
+		"same flags as when creating a volume.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		fields := make(map[string]interface{})
+		if c.IsSet(descriptionFlag.Name) {
+			fields["description"] = c.String(descriptionFlag.Name)
+		}
+		if c.IsSet(accessModeFlag.Name) {
+			fields["access_mode"] = c.String(accessModeFlag.Name)
+		}
+		credentials, err := volumeCredentials(c)
+		if err != nil {
+			return err
+		}
+		if credentials != (cgc.VolumeCredentials{}) {
+			fields["service"] = map[string]interface{}{"credentials": credentials}
+		}
+		if len(fields) == 0 {
+			return fmt.Errorf("nothing to update")
+		}
+
+		volume, err := client.UpdateVolume(c.String(volumeFlag.Name), fields)
+		if err != nil {
+			return err
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(volume.Redacted())
+	},
+}
+
+var volumesDeactivateCmd = cli.Command{
+	Name:  "deactivate",
+	Usage: fmt.Sprintf("Deactivates a volume provided with '%s' flag.", volumeFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		_, err := client.DeactivateVolume(c.String(volumeFlag.Name))
+		return err
+	},
+}
+
+var volumesDeleteCmd = cli.Command{
+	Name:  "delete",
+	Usage: fmt.Sprintf("Deletes a deactivated volume provided with '%s' flag.", volumeFlag.Name),
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		volume, err := client.StatVolume(c.String(volumeFlag.Name))
+		if err != nil {
+			return err
+		}
+		if volume.Active {
+			return fmt.Errorf("volume '%s' has to be deactivated before it's deleted", volume.ID)
+		}
+
+		prompt := fmt.Sprintf("Delete volume '%s' connected to bucket '%s'?", volume.ID, volume.Service.Bucket)
+		if !c.Bool(yesFlag.Name) && !confirm(prompt) {
+			return fmt.Errorf("deleting volume '%s' cancelled", volume.ID)
+		}
+
+		return client.DeleteVolume(volume.ID)
+	},
+}
+
+var volumesBrowseCmd = cli.Command{
+	Name: "browse",
+	Usage: fmt.Sprintf(
+		"Lists the contents of a volume provided with '%s' flag under the prefix provided with '%s' flag.",
+		volumeFlag.Name, prefixFlag.Name,
+	),
+	UsageText: "Objects nested deeper than the prefix are grouped, like directories, and printed with a trailing '/'.",
+	Action: func(c *cli.Context) error {
+		client := newClient(c)
+
+		opts := cgc.ListOptions{Limit: c.Int(limitFlag.Name)}
+		it := client.IterateVolume(c.String(volumeFlag.Name), c.String(prefixFlag.Name), opts)
+		for it.Next() {
+			entry := it.Item()
+			if entry.Prefix != "" {
+				fmt.Println(entry.Prefix)
+			} else {
+				fmt.Println(entry.Location)
+			}
+		}
+
+		return it.Err()
+	},
+}
+
+// volumeCredentials builds the volume credentials out of the flags, reading the secrets from the files the flags
+// point to.
+func volumeCredentials(c *cli.Context) (cgc.VolumeCredentials, error) {
+	credentials := cgc.VolumeCredentials{
+		AccessKeyID: c.String(accessKeyIDFlag.Name),
+		RoleARN:     c.String(roleARNFlag.Name),
+		ExternalID:  c.String(externalIDFlag.Name),
+	}
+
+	if path := c.String(secretAccessKeyFileFlag.Name); path != "" {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return cgc.VolumeCredentials{}, fmt.Errorf("reading secret access key failed: %s", err.Error())
+		}
+		credentials.SecretAccessKey = strings.TrimSpace(string(bs))
+	}
+
+	if path := c.String(gcsKeyFileFlag.Name); path != "" {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return cgc.VolumeCredentials{}, fmt.Errorf("reading service account key failed: %s", err.Error())
+		}
+		var key struct {
+			ClientEmail string `json:"client_email"`
+			PrivateKey  string `json:"private_key"`
+		}
+		// the error is not included on purpose, as it could quote parts of the key
+		if err := json.Unmarshal(bs, &key); err != nil {
+			return cgc.VolumeCredentials{}, fmt.Errorf("unmarshalling service account key '%s' failed", path)
+		}
+		credentials.ClientEmail = key.ClientEmail
+		credentials.PrivateKey = key.PrivateKey
+	}
+
+	return credentials, nil
+}
+
+var volumeFlag = cli.StringFlag{
+	Usage: "represents the volume ID",
+	Name:  "volume",
+}
+var volumeNameFlag = cli.StringFlag{
+	Usage: "name of the volume",
+	Name:  "name",
+}
+var accessModeFlag = cli.StringFlag{
+	Usage: fmt.Sprintf("access mode of the volume, '%s' or '%s'", cgc.VolumeReadOnly, cgc.VolumeReadWrite),
+	Name:  "access-mode",
+	Value: cgc.VolumeReadOnly,
+}
+var serviceTypeFlag = cli.StringFlag{
+	Usage: fmt.Sprintf("type of the cloud storage service, '%s' or '%s'", cgc.VolumeS3, cgc.VolumeGCS),
+	Name:  "type",
+	Value: cgc.VolumeS3,
+}
+var bucketFlag = cli.StringFlag{
+	Usage: "name of the bucket",
+	Name:  "bucket",
+}
+var prefixFlag = cli.StringFlag{
+	Usage: "prefix of the objects in the bucket",
+	Name:  "prefix",
+}
+var endpointFlag = cli.StringFlag{
+	Usage: "endpoint of the cloud storage service, if not the default one",
+	Name:  "endpoint",
+}
+var accessKeyIDFlag = cli.StringFlag{
+	Usage: "ID of the S3 access key",
+	Name:  "access-key-id",
+}
+var secretAccessKeyFileFlag = cli.StringFlag{
+	Usage: "path to a file holding the S3 secret access key",
+	Name:  "secret-access-key-file",
+}
+var roleARNFlag = cli.StringFlag{
+	Usage: "ARN of the AWS role to assume",
+	Name:  "role-arn",
+}
+var externalIDFlag = cli.StringFlag{
+	Usage: "external ID used when assuming the AWS role",
+	Name:  "external-id",
+}
+var gcsKeyFileFlag = cli.StringFlag{
+	Usage: "path to a GCS service account key file in JSON format",
+	Name:  "gcs-key-file",
+}
+
+var volumeCredentialsFlags = []cli.Flag{
+	accessKeyIDFlag, secretAccessKeyFileFlag, roleARNFlag, externalIDFlag, gcsKeyFileFlag,
+}
+
+func init() {
+	volumesListCmd.Flags = []cli.Flag{limitFlag}
+	volumesStatCmd.Flags = []cli.Flag{volumeFlag}
+	volumesCreateCmd.Flags = append([]cli.Flag{
+		volumeNameFlag, descriptionFlag, accessModeFlag, serviceTypeFlag, bucketFlag, prefixFlag, endpointFlag,
+	}, volumeCredentialsFlags...)
+	volumesUpdateCmd.Flags = append([]cli.Flag{volumeFlag, descriptionFlag, accessModeFlag}, volumeCredentialsFlags...)
+	volumesDeactivateCmd.Flags = []cli.Flag{volumeFlag}
+	volumesDeleteCmd.Flags = []cli.Flag{volumeFlag, yesFlag}
+	volumesBrowseCmd.Flags = []cli.Flag{volumeFlag, prefixFlag, limitFlag}
+
+	volumesCmd.Subcommands = []cli.Command{
+		volumesListCmd,
+		volumesStatCmd,
+		volumesCreateCmd,
+		volumesUpdateCmd,
+		volumesDeactivateCmd,
+		volumesDeleteCmd,
+		volumesBrowseCmd,
+	}
+}